@@ -2,6 +2,9 @@ package ast
 
 import (
 	"bytes"
+	"fmt"
+	"strings"
+	"unicode"
 
 	"github.com/MichaelBo1/go_interpreter/token"
 )
@@ -9,6 +12,8 @@ import (
 type Node interface {
 	TokenLiteral() string
 	String() string
+	Pos() token.Position // Position of the first character belonging to the node.
+	End() token.Position // Position immediately after the last character belonging to the node.
 }
 
 type Statement interface {
@@ -32,6 +37,18 @@ func (p *Program) TokenLiteral() string {
 	return ""
 }
 
+func (p *Program) Pos() token.Position {
+	if len(p.Statements) > 0 {
+		return p.Statements[0].Pos()
+	}
+	return token.Position{}
+}
+func (p *Program) End() token.Position {
+	if len(p.Statements) > 0 {
+		return p.Statements[len(p.Statements)-1].End()
+	}
+	return token.Position{}
+}
 func (p *Program) String() string {
 	var result bytes.Buffer
 
@@ -63,6 +80,51 @@ func (ls *LetStatement) String() string {
 
 	return result.String()
 }
+func (ls *LetStatement) Pos() token.Position { return ls.Token.Pos }
+func (ls *LetStatement) End() token.Position {
+	if ls.Value != nil {
+		return ls.Value.End()
+	}
+	if ls.Name != nil {
+		return ls.Name.End()
+	}
+	return ls.Token.End
+}
+
+// ConstStatement binds Name like a LetStatement, except that the binding can't be assigned to
+// or declared again in the same scope.
+type ConstStatement struct {
+	Token token.Token
+	Name  *Identifier
+	Value Expression
+}
+
+func (cs *ConstStatement) statementNode()       {}
+func (cs *ConstStatement) TokenLiteral() string { return cs.Token.Literal }
+func (cs *ConstStatement) String() string {
+	var result bytes.Buffer
+
+	result.WriteString(cs.TokenLiteral() + " ")
+	result.WriteString(cs.Name.String())
+	result.WriteString(" = ")
+
+	if cs.Value != nil {
+		result.WriteString(cs.Value.String())
+	}
+	result.WriteString(";")
+
+	return result.String()
+}
+func (cs *ConstStatement) Pos() token.Position { return cs.Token.Pos }
+func (cs *ConstStatement) End() token.Position {
+	if cs.Value != nil {
+		return cs.Value.End()
+	}
+	if cs.Name != nil {
+		return cs.Name.End()
+	}
+	return cs.Token.End
+}
 
 type ReturnStatement struct {
 	Token token.Token
@@ -82,6 +144,13 @@ func (rs *ReturnStatement) String() string {
 
 	return result.String()
 }
+func (rs *ReturnStatement) Pos() token.Position { return rs.Token.Pos }
+func (rs *ReturnStatement) End() token.Position {
+	if rs.Value != nil {
+		return rs.Value.End()
+	}
+	return rs.Token.End
+}
 
 // Scripting languages often differentiate an expression statement as for example here `x + 10;` is valid code; we can have a line consisting solely of an expression;
 type ExpressionStatement struct {
@@ -97,6 +166,18 @@ func (es *ExpressionStatement) String() string {
 	}
 	return ""
 }
+func (es *ExpressionStatement) Pos() token.Position {
+	if es.Expression != nil {
+		return es.Expression.Pos()
+	}
+	return es.Token.Pos
+}
+func (es *ExpressionStatement) End() token.Position {
+	if es.Expression != nil {
+		return es.Expression.End()
+	}
+	return es.Token.End
+}
 
 type Identifier struct {
 	Token token.Token
@@ -110,6 +191,8 @@ func (i *Identifier) TokenLiteral() string {
 func (i *Identifier) String() string {
 	return i.Value
 }
+func (i *Identifier) Pos() token.Position { return i.Token.Pos }
+func (i *Identifier) End() token.Position { return i.Token.End }
 
 type IntegerLiteral struct {
 	Token token.Token
@@ -123,7 +206,130 @@ func (il *IntegerLiteral) TokenLiteral() string {
 func (il *IntegerLiteral) String() string {
 	return il.Token.Literal
 }
+func (il *IntegerLiteral) Pos() token.Position { return il.Token.Pos }
+func (il *IntegerLiteral) End() token.Position { return il.Token.End }
+
+type Boolean struct {
+	Token token.Token
+	Value bool
+}
+
+func (b *Boolean) expressionNode() {}
+func (b *Boolean) TokenLiteral() string {
+	return b.Token.Literal
+}
+func (b *Boolean) String() string {
+	return b.Token.Literal
+}
+func (b *Boolean) Pos() token.Position { return b.Token.Pos }
+func (b *Boolean) End() token.Position { return b.Token.End }
+
+type FloatLiteral struct {
+	Token token.Token
+	Value float64
+}
+
+func (fl *FloatLiteral) expressionNode() {}
+func (fl *FloatLiteral) TokenLiteral() string {
+	return fl.Token.Literal
+}
+func (fl *FloatLiteral) String() string {
+	return fl.Token.Literal
+}
+func (fl *FloatLiteral) Pos() token.Position { return fl.Token.Pos }
+func (fl *FloatLiteral) End() token.Position { return fl.Token.End }
+
+// The token literal of a StringLiteral holds the string with its escapes already decoded,
+// so String() re-escapes it to print valid source.
+type StringLiteral struct {
+	Token token.Token
+	Value string
+}
+
+func (sl *StringLiteral) expressionNode() {}
+func (sl *StringLiteral) TokenLiteral() string {
+	return sl.Token.Literal
+}
+func (sl *StringLiteral) String() string {
+	return `"` + escapeString(sl.Value) + `"`
+}
+func (sl *StringLiteral) Pos() token.Position { return sl.Token.Pos }
+func (sl *StringLiteral) End() token.Position { return sl.Token.End }
+
+// escapeString is the inverse of the escape decoding done by the lexer.
+func escapeString(value string) string {
+	var result strings.Builder
+
+	for i, ch := range value {
+		switch ch {
+		case '$':
+			if strings.HasPrefix(value[i:], "${") {
+				result.WriteString(`\$`)
+			} else {
+				result.WriteRune(ch)
+			}
+		case '"':
+			result.WriteString(`\"`)
+		case '\\':
+			result.WriteString(`\\`)
+		case '\n':
+			result.WriteString(`\n`)
+		case '\t':
+			result.WriteString(`\t`)
+		case '\r':
+			result.WriteString(`\r`)
+		default:
+			if unicode.IsControl(ch) {
+				fmt.Fprintf(&result, `\u{%x}`, ch)
+			} else {
+				result.WriteRune(ch)
+			}
+		}
+	}
+
+	return result.String()
+}
+
+// InterpolatedString is a string literal containing `${...}` expressions. Segments
+// alternates between the literal text, as *StringLiteral, and the interpolated expressions,
+// always starting and ending with text, so "a${x}b" has the segments "a", x and "b".
+type InterpolatedString struct {
+	Token    token.Token // The STRING_START token.
+	Segments []Expression
+	EndToken token.Token // The STRING_END token.
+}
+
+func (is *InterpolatedString) expressionNode() {}
+func (is *InterpolatedString) TokenLiteral() string {
+	return is.Token.Literal
+}
+func (is *InterpolatedString) String() string {
+	var result bytes.Buffer
+
+	result.WriteString(`"`)
+	for i, segment := range is.Segments {
+		if i%2 == 0 {
+			result.WriteString(escapeString(segment.(*StringLiteral).Value))
+		} else {
+			result.WriteString("${")
+			result.WriteString(segment.String())
+			result.WriteString("}")
+		}
+	}
+	result.WriteString(`"`)
+
+	return result.String()
+}
+func (is *InterpolatedString) Pos() token.Position { return is.Token.Pos }
+func (is *InterpolatedString) End() token.Position {
+	if is.EndToken.End.IsValid() {
+		return is.EndToken.End
+	}
+	return is.Token.End
+}
 
+// Prefix and infix expressions print fully parenthesised so that the parsed precedence
+// is visible in the output, e.g. `-a * b` prints as `((-a) * b)`.
 type PrefixExpression struct {
 	Token    token.Token
 	Operator string
@@ -144,3 +350,492 @@ func (pe *PrefixExpression) String() string {
 
 	return result.String()
 }
+func (pe *PrefixExpression) Pos() token.Position { return pe.Token.Pos }
+func (pe *PrefixExpression) End() token.Position {
+	if pe.Right != nil {
+		return pe.Right.End()
+	}
+	return pe.Token.End
+}
+
+type InfixExpression struct {
+	Token    token.Token
+	Left     Expression
+	Operator string
+	Right    Expression
+}
+
+func (ie *InfixExpression) expressionNode() {}
+func (ie *InfixExpression) TokenLiteral() string {
+	return ie.Token.Literal
+}
+func (ie *InfixExpression) String() string {
+	var result bytes.Buffer
+
+	result.WriteString("(")
+	result.WriteString(ie.Left.String())
+	result.WriteString(" " + ie.Operator + " ")
+	result.WriteString(ie.Right.String())
+	result.WriteString(")")
+
+	return result.String()
+}
+func (ie *InfixExpression) Pos() token.Position {
+	if ie.Left != nil {
+		return ie.Left.Pos()
+	}
+	return ie.Token.Pos
+}
+func (ie *InfixExpression) End() token.Position {
+	if ie.Right != nil {
+		return ie.Right.End()
+	}
+	return ie.Token.End
+}
+
+// AssignExpression stores Value in Target, which the parser only allows to be an Identifier
+// or IndexExpression. For a compound operator such as `+=`, Operator includes the '='.
+type AssignExpression struct {
+	Token    token.Token // The '=' or compound assignment token.
+	Target   Expression
+	Operator string
+	Value    Expression
+}
+
+func (ae *AssignExpression) expressionNode() {}
+func (ae *AssignExpression) TokenLiteral() string {
+	return ae.Token.Literal
+}
+func (ae *AssignExpression) String() string {
+	var result bytes.Buffer
+
+	result.WriteString("(")
+	result.WriteString(ae.Target.String())
+	result.WriteString(" " + ae.Operator + " ")
+	result.WriteString(ae.Value.String())
+	result.WriteString(")")
+
+	return result.String()
+}
+func (ae *AssignExpression) Pos() token.Position {
+	if ae.Target != nil {
+		return ae.Target.Pos()
+	}
+	return ae.Token.Pos
+}
+func (ae *AssignExpression) End() token.Position {
+	if ae.Value != nil {
+		return ae.Value.End()
+	}
+	return ae.Token.End
+}
+
+// LogicalExpression is an `&&` or `||`. It is kept apart from InfixExpression because its
+// right operand is only evaluated when the left one doesn't already decide the result.
+type LogicalExpression struct {
+	Token    token.Token // The '&&' or '||' token.
+	Left     Expression
+	Operator string
+	Right    Expression
+}
+
+func (le *LogicalExpression) expressionNode() {}
+func (le *LogicalExpression) TokenLiteral() string {
+	return le.Token.Literal
+}
+func (le *LogicalExpression) String() string {
+	var result bytes.Buffer
+
+	result.WriteString("(")
+	result.WriteString(le.Left.String())
+	result.WriteString(" " + le.Operator + " ")
+	result.WriteString(le.Right.String())
+	result.WriteString(")")
+
+	return result.String()
+}
+func (le *LogicalExpression) Pos() token.Position {
+	if le.Left != nil {
+		return le.Left.Pos()
+	}
+	return le.Token.Pos
+}
+func (le *LogicalExpression) End() token.Position {
+	if le.Right != nil {
+		return le.Right.End()
+	}
+	return le.Token.End
+}
+
+// RangeExpression is `a..b`, which runs from a up to but not including b, or `a..=b`,
+// which includes b.
+type RangeExpression struct {
+	Token     token.Token // The '..' or '..=' token.
+	Low       Expression
+	High      Expression
+	Inclusive bool
+}
+
+func (re *RangeExpression) expressionNode() {}
+func (re *RangeExpression) TokenLiteral() string {
+	return re.Token.Literal
+}
+func (re *RangeExpression) String() string {
+	var result bytes.Buffer
+
+	result.WriteString("(")
+	result.WriteString(re.Low.String())
+	result.WriteString(re.Token.Literal)
+	result.WriteString(re.High.String())
+	result.WriteString(")")
+
+	return result.String()
+}
+func (re *RangeExpression) Pos() token.Position {
+	if re.Low != nil {
+		return re.Low.Pos()
+	}
+	return re.Token.Pos
+}
+func (re *RangeExpression) End() token.Position {
+	if re.High != nil {
+		return re.High.End()
+	}
+	return re.Token.End
+}
+
+type BlockStatement struct {
+	Token      token.Token // The '{' token.
+	Statements []Statement
+	Rbrace     token.Token
+}
+
+func (bs *BlockStatement) statementNode()       {}
+func (bs *BlockStatement) TokenLiteral() string { return bs.Token.Literal }
+func (bs *BlockStatement) String() string {
+	var result bytes.Buffer
+
+	result.WriteString("{ ")
+	for _, s := range bs.Statements {
+		result.WriteString(s.String())
+	}
+	result.WriteString(" }")
+
+	return result.String()
+}
+func (bs *BlockStatement) Pos() token.Position { return bs.Token.Pos }
+func (bs *BlockStatement) End() token.Position {
+	if bs.Rbrace.End.IsValid() {
+		return bs.Rbrace.End
+	}
+	if len(bs.Statements) > 0 {
+		return bs.Statements[len(bs.Statements)-1].End()
+	}
+	return bs.Token.End
+}
+
+type WhileStatement struct {
+	Token     token.Token // The 'while' token.
+	Condition Expression
+	Body      *BlockStatement
+}
+
+func (ws *WhileStatement) statementNode()       {}
+func (ws *WhileStatement) TokenLiteral() string { return ws.Token.Literal }
+func (ws *WhileStatement) String() string {
+	var result bytes.Buffer
+
+	result.WriteString("while ")
+	result.WriteString(ws.Condition.String())
+	result.WriteString(" ")
+	result.WriteString(ws.Body.String())
+
+	return result.String()
+}
+func (ws *WhileStatement) Pos() token.Position { return ws.Token.Pos }
+func (ws *WhileStatement) End() token.Position {
+	if ws.Body != nil {
+		return ws.Body.End()
+	}
+	return ws.Token.End
+}
+
+// ForInStatement loops over the elements of Iterable. Key is nil unless two loop variables
+// were given, as in `for (k, v in h)`; with one, Value takes each array element, hash key or
+// character.
+type ForInStatement struct {
+	Token    token.Token // The 'for' token.
+	Key      *Identifier
+	Value    *Identifier
+	Iterable Expression
+	Body     *BlockStatement
+}
+
+func (fs *ForInStatement) statementNode()       {}
+func (fs *ForInStatement) TokenLiteral() string { return fs.Token.Literal }
+func (fs *ForInStatement) String() string {
+	var result bytes.Buffer
+
+	result.WriteString("for ")
+	if fs.Key != nil {
+		result.WriteString(fs.Key.String() + ", ")
+	}
+	result.WriteString(fs.Value.String())
+	result.WriteString(" in ")
+	result.WriteString(fs.Iterable.String())
+	result.WriteString(" ")
+	result.WriteString(fs.Body.String())
+
+	return result.String()
+}
+func (fs *ForInStatement) Pos() token.Position { return fs.Token.Pos }
+func (fs *ForInStatement) End() token.Position {
+	if fs.Body != nil {
+		return fs.Body.End()
+	}
+	return fs.Token.End
+}
+
+type BreakStatement struct {
+	Token token.Token
+}
+
+func (bs *BreakStatement) statementNode()       {}
+func (bs *BreakStatement) TokenLiteral() string { return bs.Token.Literal }
+func (bs *BreakStatement) String() string       { return bs.TokenLiteral() + ";" }
+func (bs *BreakStatement) Pos() token.Position  { return bs.Token.Pos }
+func (bs *BreakStatement) End() token.Position  { return bs.Token.End }
+
+type ContinueStatement struct {
+	Token token.Token
+}
+
+func (cs *ContinueStatement) statementNode()       {}
+func (cs *ContinueStatement) TokenLiteral() string { return cs.Token.Literal }
+func (cs *ContinueStatement) String() string       { return cs.TokenLiteral() + ";" }
+func (cs *ContinueStatement) Pos() token.Position  { return cs.Token.Pos }
+func (cs *ContinueStatement) End() token.Position  { return cs.Token.End }
+
+// An `else if` chain is represented as an Alternative block holding a single IfExpression.
+type IfExpression struct {
+	Token       token.Token
+	Condition   Expression
+	Consequence *BlockStatement
+	Alternative *BlockStatement
+}
+
+func (ie *IfExpression) expressionNode() {}
+func (ie *IfExpression) TokenLiteral() string {
+	return ie.Token.Literal
+}
+func (ie *IfExpression) String() string {
+	var result bytes.Buffer
+
+	result.WriteString("if ")
+	result.WriteString(ie.Condition.String())
+	result.WriteString(" ")
+	result.WriteString(ie.Consequence.String())
+
+	if ie.Alternative != nil {
+		result.WriteString(" else ")
+		result.WriteString(ie.Alternative.String())
+	}
+
+	return result.String()
+}
+func (ie *IfExpression) Pos() token.Position { return ie.Token.Pos }
+func (ie *IfExpression) End() token.Position {
+	if ie.Alternative != nil {
+		return ie.Alternative.End()
+	}
+	if ie.Consequence != nil {
+		return ie.Consequence.End()
+	}
+	return ie.Token.End
+}
+
+type FunctionLiteral struct {
+	Token      token.Token // The 'fn' token.
+	Parameters []*Identifier
+	Body       *BlockStatement
+}
+
+func (fl *FunctionLiteral) expressionNode() {}
+func (fl *FunctionLiteral) TokenLiteral() string {
+	return fl.Token.Literal
+}
+func (fl *FunctionLiteral) String() string {
+	var result bytes.Buffer
+
+	params := []string{}
+	for _, p := range fl.Parameters {
+		params = append(params, p.String())
+	}
+
+	result.WriteString(fl.TokenLiteral())
+	result.WriteString("(")
+	result.WriteString(strings.Join(params, ", "))
+	result.WriteString(") ")
+	result.WriteString(fl.Body.String())
+
+	return result.String()
+}
+func (fl *FunctionLiteral) Pos() token.Position { return fl.Token.Pos }
+func (fl *FunctionLiteral) End() token.Position {
+	if fl.Body != nil {
+		return fl.Body.End()
+	}
+	return fl.Token.End
+}
+
+type CallExpression struct {
+	Token     token.Token // The '(' token.
+	Function  Expression  // Either an Identifier or anything else that evaluates to a function.
+	Arguments []Expression
+	Rparen    token.Token
+}
+
+func (ce *CallExpression) expressionNode() {}
+func (ce *CallExpression) TokenLiteral() string {
+	return ce.Token.Literal
+}
+func (ce *CallExpression) String() string {
+	var result bytes.Buffer
+
+	args := []string{}
+	for _, a := range ce.Arguments {
+		args = append(args, a.String())
+	}
+
+	result.WriteString(ce.Function.String())
+	result.WriteString("(")
+	result.WriteString(strings.Join(args, ", "))
+	result.WriteString(")")
+
+	return result.String()
+}
+func (ce *CallExpression) Pos() token.Position { return ce.Function.Pos() }
+func (ce *CallExpression) End() token.Position {
+	if ce.Rparen.End.IsValid() {
+		return ce.Rparen.End
+	}
+	return ce.Token.End
+}
+
+type ArrayLiteral struct {
+	Token    token.Token // The '[' token.
+	Elements []Expression
+	Rbracket token.Token
+}
+
+func (al *ArrayLiteral) expressionNode() {}
+func (al *ArrayLiteral) TokenLiteral() string {
+	return al.Token.Literal
+}
+func (al *ArrayLiteral) String() string {
+	var result bytes.Buffer
+
+	elements := []string{}
+	for _, el := range al.Elements {
+		elements = append(elements, el.String())
+	}
+
+	result.WriteString("[")
+	result.WriteString(strings.Join(elements, ", "))
+	result.WriteString("]")
+
+	return result.String()
+}
+func (al *ArrayLiteral) Pos() token.Position { return al.Token.Pos }
+func (al *ArrayLiteral) End() token.Position { return al.Rbracket.End }
+
+type IndexExpression struct {
+	Token    token.Token // The '[' token.
+	Left     Expression
+	Index    Expression
+	Rbracket token.Token
+}
+
+func (ie *IndexExpression) expressionNode() {}
+func (ie *IndexExpression) TokenLiteral() string {
+	return ie.Token.Literal
+}
+func (ie *IndexExpression) String() string {
+	var result bytes.Buffer
+
+	result.WriteString("(")
+	result.WriteString(ie.Left.String())
+	result.WriteString("[")
+	result.WriteString(ie.Index.String())
+	result.WriteString("])")
+
+	return result.String()
+}
+func (ie *IndexExpression) Pos() token.Position { return ie.Left.Pos() }
+func (ie *IndexExpression) End() token.Position { return ie.Rbracket.End }
+
+// SliceExpression is `left[low:high]`, where either bound may be omitted and left nil.
+type SliceExpression struct {
+	Token    token.Token // The '[' token.
+	Left     Expression
+	Low      Expression
+	High     Expression
+	Rbracket token.Token
+}
+
+func (se *SliceExpression) expressionNode() {}
+func (se *SliceExpression) TokenLiteral() string {
+	return se.Token.Literal
+}
+func (se *SliceExpression) String() string {
+	var result bytes.Buffer
+
+	result.WriteString("(")
+	result.WriteString(se.Left.String())
+	result.WriteString("[")
+	if se.Low != nil {
+		result.WriteString(se.Low.String())
+	}
+	result.WriteString(":")
+	if se.High != nil {
+		result.WriteString(se.High.String())
+	}
+	result.WriteString("])")
+
+	return result.String()
+}
+func (se *SliceExpression) Pos() token.Position { return se.Left.Pos() }
+func (se *SliceExpression) End() token.Position { return se.Rbracket.End }
+
+// HashLiteral keeps its pairs in source order so it prints deterministically.
+type HashLiteral struct {
+	Token  token.Token // The '{' token.
+	Pairs  []HashPair
+	Rbrace token.Token
+}
+
+type HashPair struct {
+	Key   Expression
+	Value Expression
+}
+
+func (hl *HashLiteral) expressionNode() {}
+func (hl *HashLiteral) TokenLiteral() string {
+	return hl.Token.Literal
+}
+func (hl *HashLiteral) String() string {
+	var result bytes.Buffer
+
+	pairs := []string{}
+	for _, pair := range hl.Pairs {
+		pairs = append(pairs, pair.Key.String()+": "+pair.Value.String())
+	}
+
+	result.WriteString("{")
+	result.WriteString(strings.Join(pairs, ", "))
+	result.WriteString("}")
+
+	return result.String()
+}
+func (hl *HashLiteral) Pos() token.Position { return hl.Token.Pos }
+func (hl *HashLiteral) End() token.Position { return hl.Rbrace.End }
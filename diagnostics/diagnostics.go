@@ -0,0 +1,224 @@
+// Package diagnostics renders positioned errors against the source they came from, showing
+// the offending line with the problem span underlined, in the style of rustc:
+//
+//	error: expected next token to be ASSIGN, got INT.
+//	 --> script.mk:1:7
+//	  |
+//	1 | let x 5;
+//	  |       ^ expected ASSIGN
+package diagnostics
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/MichaelBo1/go_interpreter/parser"
+	"github.com/MichaelBo1/go_interpreter/token"
+)
+
+type Severity int
+
+const (
+	Error Severity = iota
+	Warning
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Error:
+		return "error"
+	case Warning:
+		return "warning"
+	default:
+		return "error"
+	}
+}
+
+// Diagnostic is a single report about a span of the source.
+type Diagnostic struct {
+	Severity Severity
+	Pos      token.Position
+	End      token.Position // If not after Pos, a single character is underlined.
+	Message  string         // Primary message shown in the header.
+	Label    string         // Optional short text printed next to the underline.
+	Notes    []string       // Optional extra lines printed below the snippet.
+}
+
+func FromParseError(err parser.ParseError) Diagnostic {
+	diag := Diagnostic{
+		Severity: Error,
+		Pos:      err.Pos,
+		End:      err.Found.End,
+		Message:  err.Message,
+	}
+
+	if err.Found.Pos != err.Pos {
+		diag.End = err.Pos
+	}
+	if err.Expected != token.UNKNOWN {
+		diag.Label = fmt.Sprintf("expected %s", err.Expected)
+	}
+
+	return diag
+}
+
+func FromParseErrors(errs []parser.ParseError) []Diagnostic {
+	diags := make([]Diagnostic, 0, len(errs))
+	for _, err := range errs {
+		diags = append(diags, FromParseError(err))
+	}
+	return diags
+}
+
+const (
+	ansiReset    = "\x1b[0m"
+	ansiBoldRed  = "\x1b[1;31m"
+	ansiBoldBlue = "\x1b[1;34m"
+	ansiBoldYell = "\x1b[1;33m"
+	ansiBold     = "\x1b[1m"
+)
+
+// Renderer writes diagnostics for a single source text.
+type Renderer struct {
+	Source string
+	Color  bool // Whether to emit ANSI escape codes.
+
+	lines []string
+}
+
+func NewRenderer(source string, color bool) *Renderer {
+	return &Renderer{
+		Source: source,
+		Color:  color,
+		lines:  strings.Split(source, "\n"),
+	}
+}
+
+// Fprint renders diags to w, using color only if w is a terminal.
+func Fprint(w io.Writer, source string, diags []Diagnostic) error {
+	return NewRenderer(source, IsTerminal(w)).Render(w, diags)
+}
+
+func (r *Renderer) Render(w io.Writer, diags []Diagnostic) error {
+	for i, diag := range diags {
+		if i > 0 {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+		if _, err := io.WriteString(w, r.format(diag)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Renderer) format(diag Diagnostic) string {
+	var out strings.Builder
+
+	severityColor := ansiBoldRed
+	if diag.Severity == Warning {
+		severityColor = ansiBoldYell
+	}
+
+	out.WriteString(r.paint(severityColor, diag.Severity.String()))
+	out.WriteString(r.paint(ansiBold, ": "+diag.Message))
+	out.WriteString("\n")
+
+	if !diag.Pos.IsValid() {
+		for _, note := range diag.Notes {
+			fmt.Fprintf(&out, "%s note: %s\n", r.paint(ansiBoldBlue, "="), note)
+		}
+		return out.String()
+	}
+
+	lineNumber := strconv.Itoa(diag.Pos.Line)
+	gutter := strings.Repeat(" ", len(lineNumber))
+
+	fmt.Fprintf(&out, "%s%s %s\n", gutter, r.paint(ansiBoldBlue, "-->"), diag.Pos)
+	fmt.Fprintf(&out, "%s %s\n", gutter, r.paint(ansiBoldBlue, "|"))
+
+	line := r.line(diag.Pos.Line)
+	fmt.Fprintf(&out, "%s %s %s\n", r.paint(ansiBoldBlue, lineNumber), r.paint(ansiBoldBlue, "|"), line)
+
+	padding, width := r.underline(diag, line)
+	marker := r.paint(severityColor, strings.Repeat("^", width))
+	if diag.Label != "" {
+		marker += " " + r.paint(severityColor, diag.Label)
+	}
+	fmt.Fprintf(&out, "%s %s %s%s\n", gutter, r.paint(ansiBoldBlue, "|"), padding, marker)
+
+	for _, note := range diag.Notes {
+		fmt.Fprintf(&out, "%s %s note: %s\n", gutter, r.paint(ansiBoldBlue, "="), note)
+	}
+
+	return out.String()
+}
+
+// line returns the text of the given 1-based line without its line ending.
+func (r *Renderer) line(number int) string {
+	if number < 1 || number > len(r.lines) {
+		return ""
+	}
+	return strings.TrimSuffix(r.lines[number-1], "\r")
+}
+
+// underline works out the whitespace needed to reach the start of the span and how many
+// carets to draw under it. Tabs in the line are copied into the padding so the carets
+// line up however wide the terminal renders them.
+func (r *Renderer) underline(diag Diagnostic, line string) (string, int) {
+	// Columns aren't necessarily byte counts, so the start of the line is found by searching
+	// back from the byte offset instead.
+	offset := min(max(diag.Pos.Offset, 0), len(r.Source))
+	lineStart := strings.LastIndex(r.Source[:offset], "\n") + 1
+	prefixLen := min(offset-lineStart, len(line))
+
+	var padding strings.Builder
+	for _, ch := range line[:prefixLen] {
+		if ch == '\t' {
+			padding.WriteRune('\t')
+		} else {
+			padding.WriteRune(' ')
+		}
+	}
+
+	width := 1
+	if diag.End.Offset > diag.Pos.Offset {
+		spanEnd := min(diag.End.Offset-lineStart, len(line))
+		if spanEnd > prefixLen {
+			width = utf8.RuneCountInString(line[prefixLen:spanEnd])
+		}
+	}
+
+	return padding.String(), width
+}
+
+func (r *Renderer) paint(code string, text string) string {
+	if !r.Color {
+		return text
+	}
+	return code + text + ansiReset
+}
+
+// IsTerminal reports whether w is a character device such as an interactive terminal.
+// Setting the NO_COLOR environment variable always disables color.
+func IsTerminal(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
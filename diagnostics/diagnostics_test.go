@@ -0,0 +1,143 @@
+package diagnostics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/MichaelBo1/go_interpreter/lexer"
+	"github.com/MichaelBo1/go_interpreter/parser"
+	"github.com/MichaelBo1/go_interpreter/token"
+)
+
+func TestRenderParseErrors(t *testing.T) {
+	source := "let x = 1;\nlet y 5;\n"
+
+	lex := lexer.NewWithFilename("script.mk", source)
+	par := parser.New(lex)
+	par.ParseProgram()
+
+	var out bytes.Buffer
+	if err := NewRenderer(source, false).Render(&out, FromParseErrors(par.Errors())); err != nil {
+		t.Fatalf("Render returned error: %s", err)
+	}
+
+	expected := strings.Join([]string{
+		"error: expected next token to be ASSIGN, got INT.",
+		" --> script.mk:2:7",
+		"  |",
+		"2 | let y 5;",
+		"  |       ^ expected ASSIGN",
+		"",
+	}, "\n")
+
+	if out.String() != expected {
+		t.Errorf("wrong rendering.\nexpected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
+
+func TestRenderSpanNotesAndTabs(t *testing.T) {
+	source := "\tlet value = foo + bar;"
+
+	diag := Diagnostic{
+		Severity: Warning,
+		Pos:      token.Position{Offset: 13, Line: 1, Column: 14},
+		End:      token.Position{Offset: 22, Line: 1, Column: 23},
+		Message:  "suspicious sum",
+		Label:    "this one",
+		Notes:    []string{"first note", "second note"},
+	}
+
+	var out bytes.Buffer
+	NewRenderer(source, false).Render(&out, []Diagnostic{diag})
+
+	expected := strings.Join([]string{
+		"warning: suspicious sum",
+		" --> 1:14",
+		"  |",
+		"1 | \tlet value = foo + bar;",
+		"  | \t            ^^^^^^^^^ this one",
+		"  = note: first note",
+		"  = note: second note",
+		"",
+	}, "\n")
+
+	if out.String() != expected {
+		t.Errorf("wrong rendering.\nexpected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
+
+func TestRenderAtEndOfInput(t *testing.T) {
+	source := "if (x) {\n  x\n"
+
+	lex := lexer.New(source)
+	par := parser.New(lex)
+	par.ParseProgram()
+
+	var out bytes.Buffer
+	NewRenderer(source, false).Render(&out, FromParseErrors(par.Errors()))
+
+	expected := strings.Join([]string{
+		"error: expected RBRACE to close block, got EOF.",
+		" --> 3:1",
+		"  |",
+		"3 | ",
+		"  | ^ expected RBRACE",
+		"",
+	}, "\n")
+
+	if out.String() != expected {
+		t.Errorf("wrong rendering.\nexpected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
+
+func TestRenderMultibyteSource(t *testing.T) {
+	source := "let größe 変数;"
+
+	lex := lexer.New(source)
+	par := parser.New(lex)
+	par.ParseProgram()
+
+	var out bytes.Buffer
+	NewRenderer(source, false).Render(&out, FromParseErrors(par.Errors()))
+
+	expected := strings.Join([]string{
+		"error: expected next token to be ASSIGN, got IDENTIFIER.",
+		" --> 1:11",
+		"  |",
+		"1 | let größe 変数;",
+		"  |           ^^ expected ASSIGN",
+		"",
+	}, "\n")
+
+	if out.String() != expected {
+		t.Errorf("wrong rendering.\nexpected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
+
+func TestRenderColor(t *testing.T) {
+	diag := Diagnostic{
+		Pos:     token.Position{Offset: 0, Line: 1, Column: 1},
+		Message: "bad",
+	}
+
+	var plain, colored bytes.Buffer
+	NewRenderer("x", false).Render(&plain, []Diagnostic{diag})
+	NewRenderer("x", true).Render(&colored, []Diagnostic{diag})
+
+	if strings.Contains(plain.String(), "\x1b[") {
+		t.Errorf("plain output contains ANSI escapes: %q", plain.String())
+	}
+	if !strings.Contains(colored.String(), ansiBoldRed+"error"+ansiReset) {
+		t.Errorf("colored output missing ANSI escapes: %q", colored.String())
+	}
+}
+
+func TestFprintDoesNotColorBuffers(t *testing.T) {
+	var out bytes.Buffer
+	Fprint(&out, "x", []Diagnostic{{Pos: token.Position{Line: 1, Column: 1}, Message: "bad"}})
+
+	if strings.Contains(out.String(), "\x1b[") {
+		t.Errorf("expected no ANSI escapes when writing to a buffer: %q", out.String())
+	}
+}
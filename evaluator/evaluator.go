@@ -0,0 +1,896 @@
+package evaluator
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/MichaelBo1/go_interpreter/ast"
+	"github.com/MichaelBo1/go_interpreter/object"
+)
+
+// There is only ever one true, false and null value, so we reference these rather than
+// allocating new objects every time. The same goes for the loop control signals.
+var (
+	NULL     = &object.Null{}
+	TRUE     = &object.Boolean{Value: true}
+	FALSE    = &object.Boolean{Value: false}
+	BREAK    = &object.Break{}
+	CONTINUE = &object.Continue{}
+)
+
+func Eval(node ast.Node, env *object.Environment) object.Object {
+	switch node := node.(type) {
+
+	// Statements
+	case *ast.Program:
+		return evalProgram(node, env)
+	case *ast.ExpressionStatement:
+		return Eval(node.Expression, env)
+	case *ast.BlockStatement:
+		return evalBlockStatement(node, object.NewEnclosedEnvironment(env))
+	case *ast.ReturnStatement:
+		val := Eval(node.Value, env)
+		if isError(val) || isSignal(val) {
+			return val
+		}
+		return &object.ReturnValue{Value: val}
+	case *ast.LetStatement:
+		val := Eval(node.Value, env)
+		if isError(val) || isSignal(val) {
+			return val
+		}
+		if constant, _ := env.Declared(node.Name.Value); constant {
+			return newError("cannot redeclare constant %s", node.Name.Value)
+		}
+		env.Set(node.Name.Value, val)
+	case *ast.ConstStatement:
+		return evalConstStatement(node, env)
+	case *ast.WhileStatement:
+		return evalWhileStatement(node, env)
+	case *ast.ForInStatement:
+		return evalForInStatement(node, env)
+	case *ast.BreakStatement:
+		return BREAK
+	case *ast.ContinueStatement:
+		return CONTINUE
+
+	// Expressions
+	case *ast.IntegerLiteral:
+		return &object.Integer{Value: node.Value}
+	case *ast.FloatLiteral:
+		return &object.Float{Value: node.Value}
+	case *ast.StringLiteral:
+		return &object.String{Value: node.Value}
+	case *ast.InterpolatedString:
+		return evalInterpolatedString(node, env)
+	case *ast.Boolean:
+		return nativeBoolToBooleanObject(node.Value)
+	case *ast.Identifier:
+		return evalIdentifier(node, env)
+	case *ast.PrefixExpression:
+		right := Eval(node.Right, env)
+		if isError(right) || isSignal(right) {
+			return right
+		}
+		return evalPrefixExpression(node.Operator, right)
+	case *ast.InfixExpression:
+		left := Eval(node.Left, env)
+		if isError(left) || isSignal(left) {
+			return left
+		}
+		right := Eval(node.Right, env)
+		if isError(right) || isSignal(right) {
+			return right
+		}
+		return evalInfixExpression(node.Operator, left, right)
+	case *ast.AssignExpression:
+		return evalAssignExpression(node, env)
+	case *ast.LogicalExpression:
+		return evalLogicalExpression(node, env)
+	case *ast.RangeExpression:
+		return evalRangeExpression(node, env)
+	case *ast.IfExpression:
+		return evalIfExpression(node, env)
+	case *ast.ArrayLiteral:
+		elements := evalExpressions(node.Elements, env)
+		if len(elements) == 1 && (isError(elements[0]) || isSignal(elements[0])) {
+			return elements[0]
+		}
+		return &object.Array{Elements: elements}
+	case *ast.HashLiteral:
+		return evalHashLiteral(node, env)
+	case *ast.IndexExpression:
+		left := Eval(node.Left, env)
+		if isError(left) || isSignal(left) {
+			return left
+		}
+		index := Eval(node.Index, env)
+		if isError(index) || isSignal(index) {
+			return index
+		}
+		return evalIndexExpression(left, index)
+	case *ast.SliceExpression:
+		return evalSliceExpression(node, env)
+	case *ast.FunctionLiteral:
+		return &object.Function{Parameters: node.Parameters, Body: node.Body, Env: env}
+	case *ast.CallExpression:
+		function := Eval(node.Function, env)
+		if isError(function) || isSignal(function) {
+			return function
+		}
+		args := evalExpressions(node.Arguments, env)
+		if len(args) == 1 && (isError(args[0]) || isSignal(args[0])) {
+			return args[0]
+		}
+		return applyFunction(function, args)
+	}
+
+	return nil
+}
+
+func evalProgram(program *ast.Program, env *object.Environment) object.Object {
+	var result object.Object
+
+	for _, statement := range program.Statements {
+		result = Eval(statement, env)
+
+		switch result := result.(type) {
+		case *object.ReturnValue:
+			return result.Value
+		case *object.Error:
+			return result
+		}
+	}
+
+	return result
+}
+
+// Unlike evalProgram, a block leaves a ReturnValue wrapped so that a `return` inside a
+// nested block stops evaluation of every enclosing block, not just the innermost one.
+// A break or continue is passed up the same way until it reaches its loop.
+func evalBlockStatement(block *ast.BlockStatement, env *object.Environment) object.Object {
+	var result object.Object
+
+	for _, statement := range block.Statements {
+		result = Eval(statement, env)
+
+		if result != nil {
+			switch result.Type() {
+			case object.RETURN_VALUE_OBJ, object.ERROR_OBJ, object.BREAK_OBJ, object.CONTINUE_OBJ:
+				return result
+			}
+		}
+	}
+
+	// A block that is empty or ends in a let has no value of its own, but it may still be
+	// used as one, e.g. as a function's result stored in a hash, so it evaluates to null.
+	if result == nil {
+		return NULL
+	}
+	return result
+}
+
+// The resolver already rejects misuse of constants within a program, but the REPL parses each
+// line on its own, so a constant declared on an earlier line is only known to the environment.
+func evalConstStatement(cs *ast.ConstStatement, env *object.Environment) object.Object {
+	val := Eval(cs.Value, env)
+	if isError(val) || isSignal(val) {
+		return val
+	}
+
+	if constant, ok := env.Declared(cs.Name.Value); ok {
+		if constant {
+			return newError("cannot redeclare constant %s", cs.Name.Value)
+		}
+		return newError("%s is already declared in this scope", cs.Name.Value)
+	}
+
+	env.SetConst(cs.Name.Value, val)
+	return nil
+}
+
+// A while loop is a statement, so like a let it has no value of its own.
+func evalWhileStatement(ws *ast.WhileStatement, env *object.Environment) object.Object {
+	for {
+		condition := Eval(ws.Condition, env)
+		if isError(condition) || isSignal(condition) {
+			return condition
+		}
+		if !isTruthy(condition) {
+			return nil
+		}
+
+		// Each iteration gets a fresh scope, so a let in the body doesn't see the last one's.
+		if result, stop := evalLoopBody(ws.Body, object.NewEnclosedEnvironment(env)); stop {
+			return result
+		}
+	}
+}
+
+// evalForInStatement binds the loop variables in a fresh scope for each element, which the
+// body shares. With one variable, a hash gives its keys; with two, arrays, strings and
+// ranges give each element's index along with it.
+func evalForInStatement(fs *ast.ForInStatement, env *object.Environment) object.Object {
+	iterable := Eval(fs.Iterable, env)
+	if isError(iterable) || isSignal(iterable) {
+		return iterable
+	}
+
+	var result object.Object
+	visit := func(key, value object.Object) bool {
+		loopEnv := object.NewEnclosedEnvironment(env)
+		if fs.Key != nil {
+			loopEnv.Set(fs.Key.Value, key)
+		}
+		loopEnv.Set(fs.Value.Value, value)
+
+		var stop bool
+		result, stop = evalLoopBody(fs.Body, loopEnv)
+		return !stop
+	}
+
+	switch iterable := iterable.(type) {
+	case *object.Array:
+		for i, element := range iterable.Elements {
+			if !visit(&object.Integer{Value: int64(i)}, element) {
+				break
+			}
+		}
+	case *object.String:
+		i := int64(0)
+		for _, r := range iterable.Value {
+			if !visit(&object.Integer{Value: i}, &object.String{Value: string(r)}) {
+				break
+			}
+			i++
+		}
+	case *object.Hash:
+		for _, pair := range iterable.OrderedPairs() {
+			key, value := pair.Key, pair.Value
+			if fs.Key == nil {
+				value = key
+			}
+			if !visit(key, value) {
+				break
+			}
+		}
+	case *object.Range:
+		i := int64(0)
+		for n := iterable.Low; n < iterable.High || iterable.Inclusive && n == iterable.High; n++ {
+			if !visit(&object.Integer{Value: i}, &object.Integer{Value: n}) {
+				break
+			}
+			// Stop before n overflows if the range ends at the largest integer.
+			if n == iterable.High {
+				break
+			}
+			i++
+		}
+	default:
+		return newError("iteration not supported: %s", iterable.Type())
+	}
+
+	return result
+}
+
+// evalLoopBody runs one iteration of a loop. It reports whether the loop should stop and,
+// if so, what the loop statement itself should evaluate to.
+func evalLoopBody(body *ast.BlockStatement, env *object.Environment) (object.Object, bool) {
+	switch result := evalBlockStatement(body, env).(type) {
+	case *object.Break:
+		return nil, true
+	case *object.ReturnValue, *object.Error:
+		return result, true
+	}
+	return nil, false
+}
+
+func evalIdentifier(node *ast.Identifier, env *object.Environment) object.Object {
+	val, ok := env.Get(node.Value)
+	if !ok {
+		return newError("identifier not found: %s", node.Value)
+	}
+	return val
+}
+
+func evalPrefixExpression(operator string, right object.Object) object.Object {
+	switch operator {
+	case "!":
+		return evalBangOperatorExpression(right)
+	case "-":
+		return evalMinusPrefixOperatorExpression(right)
+	case "~":
+		return evalBitwiseNotOperatorExpression(right)
+	default:
+		return newError("unknown operator: %s%s", operator, right.Type())
+	}
+}
+
+func evalBangOperatorExpression(right object.Object) object.Object {
+	if isTruthy(right) {
+		return FALSE
+	}
+	return TRUE
+}
+
+func evalMinusPrefixOperatorExpression(right object.Object) object.Object {
+	switch right := right.(type) {
+	case *object.Integer:
+		return &object.Integer{Value: -right.Value}
+	case *object.Float:
+		return &object.Float{Value: -right.Value}
+	default:
+		return newError("unknown operator: -%s", right.Type())
+	}
+}
+
+func evalBitwiseNotOperatorExpression(right object.Object) object.Object {
+	integer, ok := right.(*object.Integer)
+	if !ok {
+		return newError("unknown operator: ~%s", right.Type())
+	}
+	return &object.Integer{Value: ^integer.Value}
+}
+
+func evalInfixExpression(operator string, left, right object.Object) object.Object {
+	switch {
+	case left.Type() == object.INTEGER_OBJ && right.Type() == object.INTEGER_OBJ:
+		return evalIntegerInfixExpression(operator, left, right)
+	case isNumber(left) && isNumber(right):
+		// Mixing an integer with a float promotes the integer, so the result is a float.
+		return evalFloatInfixExpression(operator, left, right)
+	case left.Type() == object.STRING_OBJ && right.Type() == object.STRING_OBJ:
+		return evalStringInfixExpression(operator, left, right)
+	case left.Type() != right.Type():
+		return newError("type mismatch: %s %s %s", left.Type(), operator, right.Type())
+	// Booleans and null are singletons, so pointer comparison is enough here.
+	case operator == "==":
+		return nativeBoolToBooleanObject(left == right)
+	case operator == "!=":
+		return nativeBoolToBooleanObject(left != right)
+	default:
+		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+func evalIntegerInfixExpression(operator string, left, right object.Object) object.Object {
+	leftVal := left.(*object.Integer).Value
+	rightVal := right.(*object.Integer).Value
+
+	switch operator {
+	case "+":
+		return &object.Integer{Value: leftVal + rightVal}
+	case "-":
+		return &object.Integer{Value: leftVal - rightVal}
+	case "*":
+		return &object.Integer{Value: leftVal * rightVal}
+	case "/":
+		if rightVal == 0 {
+			return newError("division by zero")
+		}
+		return &object.Integer{Value: leftVal / rightVal}
+	case "%":
+		// Like Go, the remainder takes the sign of the dividend: -7 % 3 is -1.
+		if rightVal == 0 {
+			return newError("division by zero")
+		}
+		return &object.Integer{Value: leftVal % rightVal}
+	case "**":
+		if rightVal < 0 {
+			// A negative power is a fraction, so it's worked out as a float.
+			return &object.Float{Value: math.Pow(float64(leftVal), float64(rightVal))}
+		}
+		return &object.Integer{Value: integerPower(leftVal, rightVal)}
+	case "&":
+		return &object.Integer{Value: leftVal & rightVal}
+	case "|":
+		return &object.Integer{Value: leftVal | rightVal}
+	case "^":
+		return &object.Integer{Value: leftVal ^ rightVal}
+	case "<<", ">>":
+		if rightVal < 0 {
+			return newError("negative shift count: %d", rightVal)
+		}
+		if operator == "<<" {
+			return &object.Integer{Value: leftVal << rightVal}
+		}
+		return &object.Integer{Value: leftVal >> rightVal}
+	case "<":
+		return nativeBoolToBooleanObject(leftVal < rightVal)
+	case "<=":
+		return nativeBoolToBooleanObject(leftVal <= rightVal)
+	case ">":
+		return nativeBoolToBooleanObject(leftVal > rightVal)
+	case ">=":
+		return nativeBoolToBooleanObject(leftVal >= rightVal)
+	case "==":
+		return nativeBoolToBooleanObject(leftVal == rightVal)
+	case "!=":
+		return nativeBoolToBooleanObject(leftVal != rightVal)
+	default:
+		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+func evalFloatInfixExpression(operator string, left, right object.Object) object.Object {
+	leftVal := toFloat(left)
+	rightVal := toFloat(right)
+
+	switch operator {
+	case "+":
+		return &object.Float{Value: leftVal + rightVal}
+	case "-":
+		return &object.Float{Value: leftVal - rightVal}
+	case "*":
+		return &object.Float{Value: leftVal * rightVal}
+	case "/":
+		if rightVal == 0 {
+			return newError("division by zero")
+		}
+		return &object.Float{Value: leftVal / rightVal}
+	case "%":
+		if rightVal == 0 {
+			return newError("division by zero")
+		}
+		return &object.Float{Value: math.Mod(leftVal, rightVal)}
+	case "**":
+		return &object.Float{Value: math.Pow(leftVal, rightVal)}
+	case "<":
+		return nativeBoolToBooleanObject(leftVal < rightVal)
+	case "<=":
+		return nativeBoolToBooleanObject(leftVal <= rightVal)
+	case ">":
+		return nativeBoolToBooleanObject(leftVal > rightVal)
+	case ">=":
+		return nativeBoolToBooleanObject(leftVal >= rightVal)
+	case "==":
+		return nativeBoolToBooleanObject(leftVal == rightVal)
+	case "!=":
+		return nativeBoolToBooleanObject(leftVal != rightVal)
+	default:
+		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+// integerPower raises base to a non-negative exponent by repeated squaring. Like the other
+// integer operators, it wraps around on overflow.
+func integerPower(base, exponent int64) int64 {
+	result := int64(1)
+	for exponent > 0 {
+		if exponent&1 == 1 {
+			result *= base
+		}
+		base *= base
+		exponent >>= 1
+	}
+	return result
+}
+
+func isNumber(obj object.Object) bool {
+	return obj.Type() == object.INTEGER_OBJ || obj.Type() == object.FLOAT_OBJ
+}
+
+func toFloat(obj object.Object) float64 {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		return float64(obj.Value)
+	case *object.Float:
+		return obj.Value
+	default:
+		return 0
+	}
+}
+
+func evalStringInfixExpression(operator string, left, right object.Object) object.Object {
+	leftVal := left.(*object.String).Value
+	rightVal := right.(*object.String).Value
+
+	switch operator {
+	case "+":
+		return &object.String{Value: leftVal + rightVal}
+	case "==":
+		return nativeBoolToBooleanObject(leftVal == rightVal)
+	case "!=":
+		return nativeBoolToBooleanObject(leftVal != rightVal)
+	default:
+		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+func evalInterpolatedString(is *ast.InterpolatedString, env *object.Environment) object.Object {
+	var result strings.Builder
+
+	for _, segment := range is.Segments {
+		// Every value can be interpolated; strings appear as their contents and everything
+		// else as it would be printed by the REPL.
+		evaluated := Eval(segment, env)
+		if isError(evaluated) || isSignal(evaluated) {
+			return evaluated
+		}
+		if evaluated == nil {
+			evaluated = NULL
+		}
+		result.WriteString(evaluated.Inspect())
+	}
+
+	return &object.String{Value: result.String()}
+}
+
+// evalAssignExpression evaluates to the value that was stored. Assigning to a name updates
+// its existing binding wherever it is, so a closure can change a variable it captured;
+// only `let` introduces a new one.
+func evalAssignExpression(ae *ast.AssignExpression, env *object.Environment) object.Object {
+	switch target := ae.Target.(type) {
+	case *ast.Identifier:
+		var current object.Object
+		if ae.Operator != "=" {
+			current = evalIdentifier(target, env)
+			if isError(current) {
+				return current
+			}
+		}
+
+		value := evalAssignedValue(ae, current, env)
+		if isError(value) || isSignal(value) {
+			return value
+		}
+
+		if env.IsConst(target.Value) {
+			return newError("cannot assign to constant %s", target.Value)
+		}
+		if !env.Assign(target.Value, value) {
+			return newError("identifier not found: %s", target.Value)
+		}
+		return value
+	case *ast.IndexExpression:
+		left := Eval(target.Left, env)
+		if isError(left) || isSignal(left) {
+			return left
+		}
+		index := Eval(target.Index, env)
+		if isError(index) || isSignal(index) {
+			return index
+		}
+
+		var current object.Object
+		if ae.Operator != "=" {
+			current = evalIndexExpression(left, index)
+			if isError(current) {
+				return current
+			}
+		}
+
+		value := evalAssignedValue(ae, current, env)
+		if isError(value) || isSignal(value) {
+			return value
+		}
+
+		return evalIndexAssignment(left, index, value)
+	default:
+		return newError("cannot assign to %s", ae.Target.String())
+	}
+}
+
+// evalAssignedValue evaluates the right-hand side of an assignment, combining it with the
+// target's current value for a compound operator such as `+=`.
+func evalAssignedValue(ae *ast.AssignExpression, current object.Object, env *object.Environment) object.Object {
+	value := Eval(ae.Value, env)
+	if isError(value) || isSignal(value) || ae.Operator == "=" {
+		return value
+	}
+	return evalInfixExpression(strings.TrimSuffix(ae.Operator, "="), current, value)
+}
+
+func evalIndexAssignment(left, index, value object.Object) object.Object {
+	switch {
+	case left.Type() == object.ARRAY_OBJ && index.Type() == object.INTEGER_OBJ:
+		elements := left.(*object.Array).Elements
+		i, ok := normaliseIndex(index.(*object.Integer).Value, len(elements))
+		if !ok {
+			return newError("index out of range: %d with length %d", index.(*object.Integer).Value, len(elements))
+		}
+		elements[i] = value
+	case left.Type() == object.HASH_OBJ:
+		key, ok := index.(object.Hashable)
+		if !ok {
+			return newError("unusable as hash key: %s", index.Type())
+		}
+		left.(*object.Hash).Set(key, value)
+	default:
+		return newError("index assignment not supported: %s[%s]", left.Type(), index.Type())
+	}
+	return value
+}
+
+// evalLogicalExpression short-circuits: the right operand is only evaluated if the left one
+// doesn't decide the result. Like `if`, it goes by truthiness and yields whichever operand
+// decided the result, so `name || "anonymous"` gives a default for a null name.
+func evalLogicalExpression(le *ast.LogicalExpression, env *object.Environment) object.Object {
+	left := Eval(le.Left, env)
+	if isError(left) || isSignal(left) {
+		return left
+	}
+
+	switch le.Operator {
+	case "&&":
+		if !isTruthy(left) {
+			return left
+		}
+	case "||":
+		if isTruthy(left) {
+			return left
+		}
+	default:
+		return newError("unknown operator: %s", le.Operator)
+	}
+
+	return Eval(le.Right, env)
+}
+
+func evalRangeExpression(re *ast.RangeExpression, env *object.Environment) object.Object {
+	low := Eval(re.Low, env)
+	if isError(low) || isSignal(low) {
+		return low
+	}
+	high := Eval(re.High, env)
+	if isError(high) || isSignal(high) {
+		return high
+	}
+
+	for _, bound := range []object.Object{low, high} {
+		if bound.Type() != object.INTEGER_OBJ {
+			return newError("range bound must be INTEGER, got %s", bound.Type())
+		}
+	}
+
+	return &object.Range{
+		Low:       low.(*object.Integer).Value,
+		High:      high.(*object.Integer).Value,
+		Inclusive: re.Inclusive,
+	}
+}
+
+func evalIfExpression(ie *ast.IfExpression, env *object.Environment) object.Object {
+	condition := Eval(ie.Condition, env)
+	if isError(condition) || isSignal(condition) {
+		return condition
+	}
+
+	if isTruthy(condition) {
+		return Eval(ie.Consequence, env)
+	} else if ie.Alternative != nil {
+		return Eval(ie.Alternative, env)
+	}
+	return NULL
+}
+
+// Indices may be negative to count back from the end, so -1 is the last element. Indexing
+// outside the array gives null rather than an error.
+func evalIndexExpression(left, index object.Object) object.Object {
+	switch {
+	case left.Type() == object.ARRAY_OBJ && index.Type() == object.INTEGER_OBJ:
+		elements := left.(*object.Array).Elements
+		i, ok := normaliseIndex(index.(*object.Integer).Value, len(elements))
+		if !ok {
+			return NULL
+		}
+		return elements[i]
+	case left.Type() == object.STRING_OBJ && index.Type() == object.INTEGER_OBJ:
+		chars := []rune(left.(*object.String).Value)
+		i, ok := normaliseIndex(index.(*object.Integer).Value, len(chars))
+		if !ok {
+			return NULL
+		}
+		return &object.String{Value: string(chars[i])}
+	case left.Type() == object.HASH_OBJ:
+		return evalHashIndexExpression(left.(*object.Hash), index)
+	default:
+		return newError("index operator not supported: %s[%s]", left.Type(), index.Type())
+	}
+}
+
+func evalHashIndexExpression(hash *object.Hash, index object.Object) object.Object {
+	key, ok := index.(object.Hashable)
+	if !ok {
+		return newError("unusable as hash key: %s", index.Type())
+	}
+
+	value, ok := hash.Get(key)
+	if !ok {
+		return NULL
+	}
+	return value
+}
+
+func evalHashLiteral(node *ast.HashLiteral, env *object.Environment) object.Object {
+	hash := object.NewHash()
+
+	for _, pair := range node.Pairs {
+		key := Eval(pair.Key, env)
+		if isError(key) || isSignal(key) {
+			return key
+		}
+
+		hashKey, ok := key.(object.Hashable)
+		if !ok {
+			return newError("unusable as hash key: %s", key.Type())
+		}
+
+		value := Eval(pair.Value, env)
+		if isError(value) || isSignal(value) {
+			return value
+		}
+
+		hash.Set(hashKey, value)
+	}
+
+	return hash
+}
+
+func normaliseIndex(index int64, length int) (int, bool) {
+	if index < 0 {
+		index += int64(length)
+	}
+	if index < 0 || index >= int64(length) {
+		return 0, false
+	}
+	return int(index), true
+}
+
+// Slice bounds may also be negative, and are clamped to the length of the array or string
+// rather than causing an error, so a slice that starts after it ends is just empty.
+func evalSliceExpression(se *ast.SliceExpression, env *object.Environment) object.Object {
+	left := Eval(se.Left, env)
+	if isError(left) || isSignal(left) {
+		return left
+	}
+
+	var length int
+	switch left := left.(type) {
+	case *object.Array:
+		length = len(left.Elements)
+	case *object.String:
+		length = len([]rune(left.Value))
+	default:
+		return newError("slice operator not supported: %s", left.Type())
+	}
+
+	low, stop := evalSliceBound(se.Low, env, 0, length)
+	if stop != nil {
+		return stop
+	}
+	high, stop := evalSliceBound(se.High, env, length, length)
+	if stop != nil {
+		return stop
+	}
+	high = max(low, high)
+
+	switch left := left.(type) {
+	case *object.Array:
+		elements := make([]object.Object, high-low)
+		copy(elements, left.Elements[low:high])
+		return &object.Array{Elements: elements}
+	default:
+		chars := []rune(left.(*object.String).Value)
+		return &object.String{Value: string(chars[low:high])}
+	}
+}
+
+func evalSliceBound(node ast.Expression, env *object.Environment, missing int, length int) (int, object.Object) {
+	if node == nil {
+		return missing, nil
+	}
+
+	bound := Eval(node, env)
+	if isError(bound) || isSignal(bound) {
+		return 0, bound
+	}
+
+	integer, ok := bound.(*object.Integer)
+	if !ok {
+		return 0, newError("slice bound must be INTEGER, got %s", bound.Type())
+	}
+
+	value := integer.Value
+	if value < 0 {
+		value += int64(length)
+	}
+	return int(min(max(value, 0), int64(length))), nil
+}
+
+func evalExpressions(exps []ast.Expression, env *object.Environment) []object.Object {
+	var result []object.Object
+
+	for _, e := range exps {
+		evaluated := Eval(e, env)
+		if isError(evaluated) || isSignal(evaluated) {
+			return []object.Object{evaluated}
+		}
+		result = append(result, evaluated)
+	}
+
+	return result
+}
+
+func applyFunction(fn object.Object, args []object.Object) object.Object {
+	function, ok := fn.(*object.Function)
+	if !ok {
+		return newError("not a function: %s", fn.Type())
+	}
+
+	if len(args) != len(function.Parameters) {
+		return newError("wrong number of arguments: want=%d, got=%d", len(function.Parameters), len(args))
+	}
+
+	// The parameter scope doubles as the body's block scope, so we skip Eval's usual
+	// enclosing of block statements here.
+	extendedEnv := extendFunctionEnv(function, args)
+	evaluated := evalBlockStatement(function.Body, extendedEnv)
+	return unwrapReturnValue(evaluated)
+}
+
+// Parameters are bound in a scope enclosed by the environment the function was defined in,
+// not the one it is called from, which is what lets closures see their defining scope.
+func extendFunctionEnv(fn *object.Function, args []object.Object) *object.Environment {
+	env := object.NewEnclosedEnvironment(fn.Env)
+
+	for i, param := range fn.Parameters {
+		env.Set(param.Value, args[i])
+	}
+
+	return env
+}
+
+// A return only unwinds as far as the function it appears in, so the wrapper is removed
+// here to stop it from also returning from the caller.
+func unwrapReturnValue(obj object.Object) object.Object {
+	if returnValue, ok := obj.(*object.ReturnValue); ok {
+		return returnValue.Value
+	}
+	return obj
+}
+
+func nativeBoolToBooleanObject(input bool) *object.Boolean {
+	if input {
+		return TRUE
+	}
+	return FALSE
+}
+
+func isTruthy(obj object.Object) bool {
+	switch obj {
+	case NULL:
+		return false
+	case TRUE:
+		return true
+	case FALSE:
+		return false
+	default:
+		return true
+	}
+}
+
+func newError(format string, a ...any) *object.Error {
+	return &object.Error{Message: fmt.Sprintf(format, a...)}
+}
+
+func isError(obj object.Object) bool {
+	if obj != nil {
+		return obj.Type() == object.ERROR_OBJ
+	}
+	return false
+}
+
+// isSignal reports whether obj is a return, break or continue on its way to the function
+// or loop it applies to. Like an error, a signal met where a value is needed is passed on
+// unchanged rather than used, so `let y = if (c) { break }` leaves the loop.
+func isSignal(obj object.Object) bool {
+	if obj != nil {
+		switch obj.Type() {
+		case object.RETURN_VALUE_OBJ, object.BREAK_OBJ, object.CONTINUE_OBJ:
+			return true
+		}
+	}
+	return false
+}
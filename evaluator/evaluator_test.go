@@ -0,0 +1,1031 @@
+package evaluator
+
+import (
+	"testing"
+
+	"github.com/MichaelBo1/go_interpreter/ast"
+	"github.com/MichaelBo1/go_interpreter/lexer"
+	"github.com/MichaelBo1/go_interpreter/object"
+	"github.com/MichaelBo1/go_interpreter/parser"
+)
+
+func TestEvalIntegerExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"5", 5},
+		{"10", 10},
+		{"-5", -5},
+		{"--10", 10},
+		{"5 + 5 + 5 + 5 - 10", 10},
+		{"2 * 2 * 2 * 2 * 2", 32},
+		{"-50 + 100 + -50", 0},
+		{"5 * 2 + 10", 20},
+		{"5 + 2 * 10", 25},
+		{"50 / 2 * 2 + 10", 60},
+		{"2 * (5 + 10)", 30},
+		{"3 * (3 * 3) + 10", 37},
+		{"(5 + 10 * 2 + 15 / 3) * 2 + -10", 50},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		testIntegerObject(t, evaluated, test.expected)
+	}
+}
+
+func TestModuloPowerAndBitwiseOperators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"7 % 3", 1},
+		{"-7 % 3", -1},
+		{"7 % -3", 1},
+		{"2 ** 10", 1024},
+		{"2 ** 0", 1},
+		{"0 ** 0", 1},
+		{"2 ** 3 ** 2", 512},
+		{"-2 ** 2", -4},
+		{"(-2) ** 3", -8},
+		{"2 ** 63", -9223372036854775808},
+		{"12 & 10", 8},
+		{"12 | 10", 14},
+		{"12 ^ 10", 6},
+		{"~0", -1},
+		{"~5", -6},
+		{"1 << 4", 16},
+		{"-16 >> 2", -4},
+		{"1 << 64", 0},
+		{"(0xF0 & 0x3C) >> 4", 3},
+		{"let flags = 0b0101; flags | 1 << 3", 13},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		testIntegerObject(t, evaluated, test.expected)
+	}
+}
+
+func TestEvalFloatExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+	}{
+		{"3.5", 3.5},
+		{"-2.5", -2.5},
+		{"1.5 + 1.5", 3},
+		{"1 + 0.5", 1.5},
+		{"0.5 + 1", 1.5},
+		{"3 / 2.0", 1.5},
+		{"1 / 4.0 * 2", 0.5},
+		{"2.5e2 - 50", 200},
+		{"let ratio = fn(a, b) { a / b }; ratio(1.0, 8)", 0.125},
+		{"7.5 % 2", 1.5},
+		{"-7.5 % 2", -1.5},
+		{"2.0 ** 3", 8},
+		{"4 ** 0.5", 2},
+		{"2 ** -1", 0.5},
+		{"-2 ** -2", -0.25},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+
+		result, ok := evaluated.(*object.Float)
+		if !ok {
+			t.Errorf("object is not Float for %q. got=%T (%+v)", test.input, evaluated, evaluated)
+			continue
+		}
+
+		if result.Value != test.expected {
+			t.Errorf("object has wrong value for %q. got=%g, want=%g", test.input, result.Value, test.expected)
+		}
+	}
+}
+
+func TestFloatInspect(t *testing.T) {
+	tests := []struct {
+		value    float64
+		expected string
+	}{
+		{2, "2.0"},
+		{0.125, "0.125"},
+		{-3, "-3.0"},
+		{1e21, "1e+21"},
+	}
+
+	for _, test := range tests {
+		actual := (&object.Float{Value: test.value}).Inspect()
+		if actual != test.expected {
+			t.Errorf("Inspect() wrong. expected=%q, got=%q", test.expected, actual)
+		}
+	}
+}
+
+func TestEvalBooleanExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"true", true},
+		{"false", false},
+		{"1 < 2", true},
+		{"1 > 2", false},
+		{"1 <= 1", true},
+		{"2 >= 3", false},
+		{"1 == 1", true},
+		{"1 != 1", false},
+		{"true == true", true},
+		{"true != false", true},
+		{"(1 < 2) == true", true},
+		{"(1 > 2) == true", false},
+		{"1.5 < 2", true},
+		{"2 <= 1.5", false},
+		{"1 == 1.0", true},
+		{"0.1 + 0.2 != 0.3", true},
+		{"0xF0 & 0x3C == 0x30", true},
+		{"5 & 1 == 1 && 4 & 1 == 0", true},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		testBooleanObject(t, evaluated, test.expected)
+	}
+}
+
+func TestBangOperator(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"!true", false},
+		{"!false", true},
+		{"!5", false},
+		{"!!true", true},
+		{"!!5", true},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		testBooleanObject(t, evaluated, test.expected)
+	}
+}
+
+func TestLogicalExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected any
+	}{
+		{"true && true", true},
+		{"true && false", false},
+		{"false && true", false},
+		{"false || true", true},
+		{"false || false", false},
+		{"1 < 2 && 2 < 3", true},
+		{"1 > 2 || 2 > 3", false},
+		{"1 && 2", 2},
+		{"0 || 5", 0},
+		{`let name = if (false) { "x" }; name || 7`, 7},
+		{"false && 1", false},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		switch expected := test.expected.(type) {
+		case bool:
+			testBooleanObject(t, evaluated, expected)
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		}
+	}
+}
+
+func TestLogicalExpressionsShortCircuit(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		// Each right-hand side would be an error if it were evaluated.
+		{"false && missing", false},
+		{"true || missing", true},
+		{"false && (1 / 0)", false},
+		{"true || fn() { 1 }()()", true},
+		{"let f = fn(n) { n > 0 && f(n - 1) || n == 0 }; f(3)", true},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		testBooleanObject(t, evaluated, test.expected)
+	}
+}
+
+func TestIfElseExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected any
+	}{
+		{"if (true) { 10 }", 10},
+		{"if (false) { 10 }", nil},
+		{"if (true) { }", nil},
+		{"if (true) { let x = 1; }", nil},
+		{"if (1) { 10 }", 10},
+		{"if (1 < 2) { 10 }", 10},
+		{"if (1 > 2) { 10 } else { 20 }", 20},
+		{"if (1 > 2) { 10 } else if (2 > 1) { 30 } else { 20 }", 30},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		integer, ok := test.expected.(int)
+		if ok {
+			testIntegerObject(t, evaluated, int64(integer))
+		} else {
+			testNullObject(t, evaluated)
+		}
+	}
+}
+
+func TestReturnStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"return 10;", 10},
+		{"return 10; 9;", 10},
+		{"return 2 * 5; 9;", 10},
+		{"9; return 2 * 5; 9;", 10},
+		{`
+		if (10 > 1) {
+			if (10 > 1) {
+				return 10;
+			}
+			return 1;
+		}`, 10},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		testIntegerObject(t, evaluated, test.expected)
+	}
+}
+
+func TestErrorHandling(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{"5 + true;", "type mismatch: INTEGER + BOOLEAN"},
+		{"5 + true; 5;", "type mismatch: INTEGER + BOOLEAN"},
+		{"-true", "unknown operator: -BOOLEAN"},
+		{"true + false;", "unknown operator: BOOLEAN + BOOLEAN"},
+		{"5; true + false; 5", "unknown operator: BOOLEAN + BOOLEAN"},
+		{"if (10 > 1) { true + false; }", "unknown operator: BOOLEAN + BOOLEAN"},
+		{"if (10 > 1) { if (10 > 1) { return true + false; } return 1; }", "unknown operator: BOOLEAN + BOOLEAN"},
+		{"foobar", "identifier not found: foobar"},
+		{"10 / 0", "division by zero"},
+		{"1.5 / 0", "division by zero"},
+		{"1.5 + true", "type mismatch: FLOAT + BOOLEAN"},
+		{"5(1)", "not a function: INTEGER"},
+		{"fn(x) { x }(1, 2)", "wrong number of arguments: want=1, got=2"},
+		{`"Hello" - "World"`, "unknown operator: STRING - STRING"},
+		{`"Hello" + 1`, "type mismatch: STRING + INTEGER"},
+		{`"value: ${missing}"`, "identifier not found: missing"},
+		{"1[0]", "index operator not supported: INTEGER[INTEGER]"},
+		{`[1]["a"]`, "index operator not supported: ARRAY[STRING]"},
+		{"[1, 2][true:]", "slice bound must be INTEGER, got BOOLEAN"},
+		{"5[1:2]", "slice operator not supported: INTEGER"},
+		{"true && missing", "identifier not found: missing"},
+		{"while (missing) { 1 }", "identifier not found: missing"},
+		{"for (x in 5) { x }", "iteration not supported: INTEGER"},
+		{"for (x in missing) { x }", "identifier not found: missing"},
+		{"for (x in [1]) { x + true }", "type mismatch: INTEGER + BOOLEAN"},
+		{"1..true", "range bound must be INTEGER, got BOOLEAN"},
+		{"x = 1", "identifier not found: x"},
+		{"x += 1", "identifier not found: x"},
+		{"let x = 1; x += true", "type mismatch: INTEGER + BOOLEAN"},
+		{"let x = 1; x /= 0", "division by zero"},
+		{"let a = [1]; a[1] = 2", "index out of range: 1 with length 1"},
+		{"let a = [1]; a[true] = 2", "index assignment not supported: ARRAY[BOOLEAN]"},
+		{`let s = "abc"; s[0] = "x"`, "index assignment not supported: STRING[INTEGER]"},
+		{"let h = {}; h[[1]] = 2", "unusable as hash key: ARRAY"},
+		{`let h = {}; h["k"] += 1`, "type mismatch: NULL + INTEGER"},
+		{"let x = 1; x = missing", "identifier not found: missing"},
+		{"1.5..2", "range bound must be INTEGER, got FLOAT"},
+		{"while (true) { 1 + true }", "type mismatch: INTEGER + BOOLEAN"},
+		{"5 % 0", "division by zero"},
+		{"5.0 % 0", "division by zero"},
+		{"1 << -1", "negative shift count: -1"},
+		{"1.5 & 1", "unknown operator: FLOAT & INTEGER"},
+		{"1 | 2.0", "unknown operator: INTEGER | FLOAT"},
+		{"~1.5", "unknown operator: ~FLOAT"},
+		{"~true", "unknown operator: ~BOOLEAN"},
+		{`"a" % "b"`, "unknown operator: STRING % STRING"},
+		{"missing || true", "identifier not found: missing"},
+		{`{"name": "Monkey"}[fn(x) { x }];`, "unusable as hash key: FUNCTION"},
+		{`{[1]: 2}`, "unusable as hash key: ARRAY"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned for %q. got=%T(%+v)", test.input, evaluated, evaluated)
+			continue
+		}
+
+		if errObj.Message != test.expectedMessage {
+			t.Errorf("wrong error message. expected=%q, got=%q", test.expectedMessage, errObj.Message)
+		}
+	}
+}
+
+func TestWhileStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected any
+	}{
+		{"while (false) { 10 }", nil},
+		{"while (true) { break; 10 }", nil},
+		{"let x = 1; while (x > 5) { missing }; x", 1},
+		{"let f = fn() { while (true) { return 5; } }; f()", 5},
+		{"let f = fn() { while (true) { while (true) { break } return 7 } }; f()", 7},
+		{"let f = fn() { while (true) { if (true) { return 3 } } }; f()", 3},
+		{"let f = fn(n) { while (n > 0) { let n = n - 1; if (n < 0) { return 1 } break } 2 }; f(3)", 2},
+		// A break or continue in the middle of an expression still applies to the loop.
+		{"let x = 0; while (true) { let y = if (true) { break }; x = 1; }; x", 0},
+		{"let x = 0; while (x < 3) { x += 1; const y = if (x < 3) { continue }; x = 10 }; x", 10},
+		{"let x = 0; while (true) { x = if (true) { break }; }; x", 0},
+		{"let x = 0; let f = fn(a) { a }; while (true) { f(if (true) { break }); x = 1 }; x", 0},
+		{"let x = 0; while (true) { [1, if (true) { break }]; x = 1 }; x", 0},
+		{"let x = 0; while (true) { {1: if (true) { break }}; x = 1 }; x", 0},
+		{"let x = 0; while (true) { 1 + if (true) { break }; x = 1 }; x", 0},
+		{"let x = 0; while (true) { -if (true) { break }; x = 1 }; x", 0},
+		{"while (true) { [1, 2, 3][if (true) { break }:] }", nil},
+		{"let f = fn() { [1, 2, 3][:if (true) { return 1 }] }; f()", 1},
+		{"let f = fn() { let y = if (true) { return 4 }; 5 }; f()", 4},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		integer, ok := test.expected.(int)
+		if ok {
+			testIntegerObject(t, evaluated, int64(integer))
+		} else if evaluated != nil {
+			t.Errorf("object is not nil for %q. got=%T (%+v)", test.input, evaluated, evaluated)
+		}
+	}
+}
+
+func TestRangeExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1..5", "1..5"},
+		{"0..=10", "0..=10"},
+		{"let n = 3; n - 1..n * 2", "2..6"},
+		{"5..1", "5..1"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		result, ok := evaluated.(*object.Range)
+		if !ok {
+			t.Errorf("object is not Range for %q. got=%T (%+v)", test.input, evaluated, evaluated)
+			continue
+		}
+
+		if result.Inspect() != test.expected {
+			t.Errorf("wrong range for %q. expected=%q, got=%q", test.input, test.expected, result.Inspect())
+		}
+	}
+}
+
+func TestForInStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected any
+	}{
+		// Without assignment, a loop's effects are only visible through what it returns.
+		{"let f = fn() { for (x in [4, 5, 6]) { return x } }; f()", 4},
+		{"let f = fn() { for (x in [4, 5, 6]) { if (x > 4) { return x } } }; f()", 5},
+		{"let f = fn() { for (i, x in [4, 5, 6]) { if (x == 6) { return i } } }; f()", 2},
+		{"let f = fn() { for (x in 1..10) { if (x < 5) { continue } return x } }; f()", 5},
+		{"let f = fn() { for (x in 1..3) { if (x == 3) { return x } }; 0 }; f()", 0},
+		{"let f = fn() { for (x in 1..=3) { if (x == 3) { return x } }; 0 }; f()", 3},
+		{"let f = fn() { for (x in 3..1) { return x }; 0 }; f()", 0},
+		{"let f = fn() { for (i, x in 10..20) { if (x == 15) { return i } } }; f()", 5},
+		{"let f = fn() { for (i in 0..1000000000000) { if (i == 3) { return i } } }; f()", 3},
+		{"let f = fn() { for (i in 9223372036854775806..=9223372036854775807) { if (i < 0) { return 0 } }; 1 }; f()", 1},
+		{`let f = fn() { for (k in {"a": 1, "b": 2}) { if (k == "b") { return 1 } } }; f()`, 1},
+		{`let f = fn() { for (k, v in {"a": 1, "b": 2}) { if (k == "b") { return v } } }; f()`, 2},
+		{`let f = fn() { for (c in "héllo") { if (c == "é") { return 1 } } }; f()`, 1},
+		{`let f = fn() { for (i, c in "héllo") { if (c == "l") { return i } } }; f()`, 2},
+		{"let f = fn() { for (x in [1, 2]) { for (y in [3, 4]) { break } return x } }; f()", 1},
+		{"for (x in [1, 2, 3]) { x }", nil},
+		{"for (x in []) { missing }", nil},
+		{"let x = 7; for (x in [1]) { }; x", 7},
+		{"let x = 0; for (i in 0..3) { let y = if (i == 1) { break }; x = i }; x", 0},
+		{"let x = 0; for (i in 0..3) { let y = if (i == 1) { continue }; x += i }; x", 2},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		integer, ok := test.expected.(int)
+		if ok {
+			testIntegerObject(t, evaluated, int64(integer))
+		} else if evaluated != nil {
+			t.Errorf("object is not nil for %q. got=%T (%+v)", test.input, evaluated, evaluated)
+		}
+	}
+}
+
+func TestForInHashOrder(t *testing.T) {
+	input := `let h = {"z": 1, "a": 2, "m": 3};
+	let first = fn(a, b) { for (k in h) { if (k == a || k == b) { continue } return k } };
+	[first("", ""), first("z", ""), first("z", "a")]`
+
+	evaluated := testEval(input)
+	if evaluated.Inspect() != `["z", "a", "m"]` {
+		t.Errorf("keys not visited in insertion order. got=%s", evaluated.Inspect())
+	}
+}
+
+func TestAssignExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"let x = 1; x = 2; x", 2},
+		{"let x = 1; x = x + 1", 2},
+		{"let a = 1; let b = 2; a = b = 3; a + b", 6},
+		{"let x = 10; x += 5; x", 15},
+		{"let x = 10; x -= 5; x", 5},
+		{"let x = 10; x *= 5; x", 50},
+		{"let x = 10; x /= 5; x", 2},
+		{"let x = 10; x %= 4; x", 2},
+		{"let x = 1; if (true) { x = 2 }; x", 2},
+		{"let x = 1; if (true) { let x = 5; x = 2 }; x", 1},
+		{"let x = 1; let f = fn() { x = x + 1 }; f(); f(); x", 3},
+		{"let f = fn(n) { n = n * 2; n }; f(4)", 8},
+		{"let a = [1, 2, 3]; a[0] = 10; a[0] + a[1]", 12},
+		{"let a = [1, 2, 3]; a[-1] += 5; a[2]", 8},
+		{"let a = [[1], [2]]; a[1][0] = 7; a[1][0]", 7},
+		{"let a = [1]; let b = a; b[0] = 9; a[0]", 9},
+		{`let h = {}; h["k"] = 1; h["k"] += 2; h["k"]`, 3},
+		{`let h = {"a": 1}; h[true] = 2; h[true] + h["a"]`, 3},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		testIntegerObject(t, evaluated, test.expected)
+	}
+}
+
+func TestConstStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"const a = 5; a;", 5},
+		{"const a = 5 * 5; a;", 25},
+		{"const a = 5; const b = a; const c = a + b + 5; c;", 15},
+		{"const a = 1; if (true) { let a = 2; a = 3; a }", 3},
+		{"const a = [1, 2]; a[0] = 9; a[0]", 9},
+		{"const f = fn(n) { n * 2 }; f(4)", 8},
+	}
+
+	for _, test := range tests {
+		testIntegerObject(t, testEval(test.input), test.expected)
+	}
+}
+
+func TestConstantsAcrossPrograms(t *testing.T) {
+	// The REPL parses each line as its own program, so only the environment knows about
+	// constants declared on earlier lines.
+	tests := []struct {
+		lines    []string
+		expected string
+	}{
+		{[]string{"const x = 1", "x = 2"}, "cannot assign to constant x"},
+		{[]string{"const x = 1", "x += 2"}, "cannot assign to constant x"},
+		{[]string{"const x = 1", "let x = 2"}, "cannot redeclare constant x"},
+		{[]string{"const x = 1", "const x = 2"}, "cannot redeclare constant x"},
+		{[]string{"let x = 1", "const x = 2"}, "x is already declared in this scope"},
+		{[]string{"const x = 1", "let f = fn() { x = 2 }", "f()"}, "cannot assign to constant x"},
+	}
+
+	for _, test := range tests {
+		env := object.NewEnvironment()
+
+		var evaluated object.Object
+		for _, line := range test.lines {
+			program := parser.New(lexer.New(line)).ParseProgram()
+			evaluated = Eval(program, env)
+		}
+
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned for %q. got=%T(%+v)", test.lines, evaluated, evaluated)
+			continue
+		}
+
+		if errObj.Message != test.expected {
+			t.Errorf("wrong error message. expected=%q, got=%q", test.expected, errObj.Message)
+		}
+
+		if val, _ := env.Get("x"); val.(*object.Integer).Value != 1 {
+			t.Errorf("x was changed by %q. got=%s", test.lines, val.Inspect())
+		}
+	}
+}
+
+func TestAssignmentInsertsHashKeysInOrder(t *testing.T) {
+	evaluated := testEval(`let h = {"b": 1}; h["a"] = 2; h["b"] = 3; h`)
+	if evaluated.Inspect() != `{"b": 3, "a": 2}` {
+		t.Errorf("wrong hash. got=%s", evaluated.Inspect())
+	}
+}
+
+func TestLoopsWithAssignment(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"let i = 0; while (i < 10) { i += 1 }; i", 10},
+		{"let sum = 0; for (x in 1..=100) { sum += x }; sum", 5050},
+		{"let sum = 0; for (x in [1, 2, 3, 4]) { if (x % 2 == 0) { continue } sum += x }; sum", 4},
+		{"let n = 0; while (true) { n += 1; if (n == 5) { break } }; n", 5},
+		{"let n = 0; for (i in 0..10) { for (j in 0..10) { if (j == 2) { break } n += 1 } }; n", 20},
+		{"let i = 0; let n = 0; while (i < 5) { i += 1; if (i == 2) { continue } n += i }; n", 13},
+		{`let total = 0; for (k, v in {"a": 1, "b": 2}) { total += v }; total`, 3},
+		{"let a = [1, 2, 3]; for (i, x in a) { a[i] = x * x }; a[0] + a[1] + a[2]", 14},
+		{"let fib = fn(n) { let a = 0; let b = 1; for (i in 0..n) { let t = a + b; a = b; b = t }; a }; fib(50)", 12586269025},
+		// A deep loop doesn't grow the Go stack the way recursion does.
+		{"let i = 0; while (i < 100000) { i += 1 }; i", 100000},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		testIntegerObject(t, evaluated, test.expected)
+	}
+}
+
+func TestLoopControlStopsBlock(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected object.Object
+	}{
+		{"while (true) { break; missing }", BREAK},
+		{"while (true) { continue; missing }", CONTINUE},
+		{"while (true) { if (true) { continue } missing }", CONTINUE},
+	}
+
+	for _, test := range tests {
+		lex := lexer.New(test.input)
+		par := parser.New(lex)
+		program := par.ParseProgram()
+
+		// Evaluate just the body, so the signal is visible rather than consumed by the loop.
+		body := program.Statements[0].(*ast.WhileStatement).Body
+		evaluated := Eval(body, object.NewEnvironment())
+		if evaluated != test.expected {
+			t.Errorf("wrong result for %q. expected=%s, got=%s", test.input, test.expected.Inspect(), evaluated.Inspect())
+		}
+	}
+}
+
+func TestLetStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"let a = 5; a;", 5},
+		{"let a = 5 * 5; a;", 25},
+		{"let a = 5; let b = a; b;", 5},
+		{"let a = 5; let b = a; let c = a + b + 5; c;", 15},
+	}
+
+	for _, test := range tests {
+		testIntegerObject(t, testEval(test.input), test.expected)
+	}
+}
+
+func TestFunctionObject(t *testing.T) {
+	input := "fn(x) { x + 2; };"
+
+	evaluated := testEval(input)
+	fn, ok := evaluated.(*object.Function)
+	if !ok {
+		t.Fatalf("object is not Function. got=%T (%+v)", evaluated, evaluated)
+	}
+
+	if len(fn.Parameters) != 1 {
+		t.Fatalf("function has wrong parameters. Parameters=%+v", fn.Parameters)
+	}
+
+	if fn.Parameters[0].String() != "x" {
+		t.Fatalf("parameter is not 'x'. got=%q", fn.Parameters[0])
+	}
+
+	expectedBody := "{ (x + 2) }"
+	if fn.Body.String() != expectedBody {
+		t.Fatalf("body is not %q. got=%q", expectedBody, fn.Body.String())
+	}
+}
+
+func TestFunctionApplication(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"let identity = fn(x) { x; }; identity(5);", 5},
+		{"let identity = fn(x) { return x; }; identity(5);", 5},
+		{"let double = fn(x) { x * 2; }; double(5);", 10},
+		{"let add = fn(x, y) { x + y; }; add(5, 5);", 10},
+		{"let add = fn(x, y) { x + y; }; add(5 + 5, add(5, 5));", 20},
+		{"fn(x) { x; }(5)", 5},
+		{"let early = fn(x) { if (x > 0) { return 1; } return 0; }; early(3) + 1;", 2},
+	}
+
+	for _, test := range tests {
+		testIntegerObject(t, testEval(test.input), test.expected)
+	}
+}
+
+func TestClosures(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{`
+		let newAdder = fn(x) {
+			fn(y) { x + y };
+		};
+		let addTwo = newAdder(2);
+		addTwo(2);`, 4},
+		{`
+		let counter = fn(start) {
+			fn(step) { start + step };
+		};
+		let fromTen = counter(10);
+		let fromHundred = counter(100);
+		fromTen(1) + fromHundred(1);`, 112},
+		{`
+		let makeCounter = fn() {
+			let count = 0;
+			fn() { count = count + 1; count };
+		};
+		let c = makeCounter();
+		c(); c();`, 2},
+		{"let x = 10; let f = fn() { x }; let g = fn() { let x = 20; f() }; g();", 10},
+	}
+
+	for _, test := range tests {
+		testIntegerObject(t, testEval(test.input), test.expected)
+	}
+}
+
+func TestRecursiveFunctions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{`
+		let fib = fn(n) {
+			if (n < 2) { return n; }
+			fib(n - 1) + fib(n - 2);
+		};
+		fib(15);`, 610},
+		{`
+		let fact = fn(n) { if (n == 0) { 1 } else { n * fact(n - 1) } };
+		fact(10);`, 3628800},
+	}
+
+	for _, test := range tests {
+		testIntegerObject(t, testEval(test.input), test.expected)
+	}
+}
+
+func TestNestedScopes(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"let x = 1; if (true) { let x = 2; x }", 2},
+		{"let x = 1; if (true) { let x = 2; }; x", 1},
+		{"let x = 1; if (true) { if (true) { if (true) { x + 1 } } }", 2},
+		{"let x = 1; if (true) { let x = 2; if (true) { let x = 3; } x }", 2},
+		{`
+		let a = 1;
+		let f = fn(b) {
+			fn(c) {
+				fn(d) { a + b + c + d }
+			}
+		};
+		f(2)(3)(4);`, 10},
+		{"let x = 1; let f = fn(x) { x * 10 }; f(5) + x;", 51},
+	}
+
+	for _, test := range tests {
+		testIntegerObject(t, testEval(test.input), test.expected)
+	}
+}
+
+func TestBlockScopeDoesNotLeak(t *testing.T) {
+	evaluated := testEval("if (true) { let y = 5; }; y")
+
+	errObj, ok := evaluated.(*object.Error)
+	if !ok {
+		t.Fatalf("expected error object. got=%T (%+v)", evaluated, evaluated)
+	}
+
+	if errObj.Message != "identifier not found: y" {
+		t.Errorf("wrong error message. got=%q", errObj.Message)
+	}
+}
+
+func TestStringLiteral(t *testing.T) {
+	evaluated := testEval(`"Hello World!"`)
+
+	str, ok := evaluated.(*object.String)
+	if !ok {
+		t.Fatalf("object is not String. got=%T (%+v)", evaluated, evaluated)
+	}
+
+	if str.Value != "Hello World!" {
+		t.Errorf("String has wrong value. got=%q", str.Value)
+	}
+}
+
+func TestStringConcatenation(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`"Hello" + " " + "World!"`, "Hello World!"},
+		{`let greet = fn(name) { "Hi, " + name }; greet("Zoë")`, "Hi, Zoë"},
+		{`"a\n" + "\u{62}"`, "a\nb"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+
+		str, ok := evaluated.(*object.String)
+		if !ok {
+			t.Fatalf("object is not String. got=%T (%+v)", evaluated, evaluated)
+		}
+
+		if str.Value != test.expected {
+			t.Errorf("String has wrong value. expected=%q, got=%q", test.expected, str.Value)
+		}
+	}
+}
+
+func TestInterpolatedStrings(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let a = 2; let b = 3; "total: ${a + b}"`, "total: 5"},
+		{`let name = "Zoë"; "hi ${name}, ${1 < 2}"`, "hi Zoë, true"},
+		{`let f = fn(x) { "<${x}>" }; "${f(1)}${f("${2}")}"`, "<1><2>"},
+		{`"\${literal}"`, "${literal}"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+
+		str, ok := evaluated.(*object.String)
+		if !ok {
+			t.Fatalf("object is not String. got=%T (%+v)", evaluated, evaluated)
+		}
+
+		if str.Value != test.expected {
+			t.Errorf("String has wrong value. expected=%q, got=%q", test.expected, str.Value)
+		}
+	}
+}
+
+func TestStringComparison(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{`"a" == "a"`, true},
+		{`"a" == "b"`, false},
+		{`"a" != "b"`, true},
+		{`"a" + "b" == "ab"`, true},
+	}
+
+	for _, test := range tests {
+		testBooleanObject(t, testEval(test.input), test.expected)
+	}
+}
+
+func TestArrayLiterals(t *testing.T) {
+	evaluated := testEval("[1, 2 * 2, 3 + 3,]")
+
+	result, ok := evaluated.(*object.Array)
+	if !ok {
+		t.Fatalf("object is not Array. got=%T (%+v)", evaluated, evaluated)
+	}
+
+	if len(result.Elements) != 3 {
+		t.Fatalf("array has wrong num of elements. got=%d", len(result.Elements))
+	}
+
+	testIntegerObject(t, result.Elements[0], 1)
+	testIntegerObject(t, result.Elements[1], 4)
+	testIntegerObject(t, result.Elements[2], 6)
+
+	if result.Inspect() != "[1, 4, 6]" {
+		t.Errorf("Inspect() wrong. got=%q", result.Inspect())
+	}
+}
+
+func TestArrayIndexExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected any
+	}{
+		{"[1, 2, 3][0]", 1},
+		{"[1, 2, 3][2]", 3},
+		{"let i = 0; [1][i];", 1},
+		{"[1, 2, 3][1 + 1];", 3},
+		{"let myArray = [1, 2, 3]; myArray[0] + myArray[1] + myArray[2];", 6},
+		{"[1, 2, 3][-1]", 3},
+		{"[1, 2, 3][-3]", 1},
+		{"[1, 2, 3][3]", nil},
+		{"[1, 2, 3][-4]", nil},
+		{"[[1, 2], [3, 4]][1][0]", 3},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		integer, ok := test.expected.(int)
+		if ok {
+			testIntegerObject(t, evaluated, int64(integer))
+		} else {
+			testNullObject(t, evaluated)
+		}
+	}
+}
+
+func TestSliceExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"[1, 2, 3, 4][1:3]", "[2, 3]"},
+		{"[1, 2, 3, 4][:2]", "[1, 2]"},
+		{"[1, 2, 3, 4][2:]", "[3, 4]"},
+		{"[1, 2, 3, 4][:]", "[1, 2, 3, 4]"},
+		{"[1, 2, 3, 4][-2:]", "[3, 4]"},
+		{"[1, 2, 3, 4][1:-1]", "[2, 3]"},
+		{"[1, 2, 3, 4][3:1]", "[]"},
+		{"[1, 2, 3, 4][-10:10]", "[1, 2, 3, 4]"},
+		{`"größe"[1:3]`, "rö"},
+		{`"hello"[-1]`, "o"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		if evaluated == nil || evaluated.Inspect() != test.expected {
+			t.Errorf("wrong result for %q. expected=%q, got=%v", test.input, test.expected, evaluated)
+		}
+	}
+}
+
+func TestSliceDoesNotAlias(t *testing.T) {
+	evaluated := testEval("let a = [1, 2, 3]; let b = a[0:2]; a")
+
+	if evaluated.Inspect() != "[1, 2, 3]" {
+		t.Errorf("wrong result. got=%q", evaluated.Inspect())
+	}
+}
+
+func TestHashLiterals(t *testing.T) {
+	input := `let two = "two";
+	{
+		"one": 10 - 9,
+		two: 1 + 1,
+		"thr" + "ee": 6 / 2,
+		4: 4,
+		true: 5,
+		false: 6
+	}`
+
+	evaluated := testEval(input)
+	result, ok := evaluated.(*object.Hash)
+	if !ok {
+		t.Fatalf("Eval didn't return Hash. got=%T (%+v)", evaluated, evaluated)
+	}
+
+	expected := map[object.HashKey]int64{
+		(&object.String{Value: "one"}).HashKey():   1,
+		(&object.String{Value: "two"}).HashKey():   2,
+		(&object.String{Value: "three"}).HashKey(): 3,
+		(&object.Integer{Value: 4}).HashKey():      4,
+		TRUE.HashKey():                             5,
+		FALSE.HashKey():                            6,
+	}
+
+	if len(result.Pairs) != len(expected) {
+		t.Fatalf("Hash has wrong num of pairs. got=%d", len(result.Pairs))
+	}
+
+	for expectedKey, expectedValue := range expected {
+		pair, ok := result.Pairs[expectedKey]
+		if !ok {
+			t.Errorf("no pair for given key in Pairs")
+		}
+
+		testIntegerObject(t, pair.Value, expectedValue)
+	}
+
+	if result.Inspect() != `{"one": 1, "two": 2, "three": 3, 4: 4, true: 5, false: 6}` {
+		t.Errorf("Inspect() not in insertion order. got=%q", result.Inspect())
+	}
+}
+
+func TestHashIndexExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected any
+	}{
+		{`{"foo": 5}["foo"]`, 5},
+		{`{"foo": 5}["bar"]`, nil},
+		{`let key = "foo"; {"foo": 5}[key]`, 5},
+		{`{}["foo"]`, nil},
+		{`{5: 5}[5]`, 5},
+		{`{true: 5}[true]`, 5},
+		{`{false: 5}[false]`, 5},
+		{`{1: 5}[true]`, nil},
+		{`{"a": 1, "a": 2}["a"]`, 2},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		integer, ok := test.expected.(int)
+		if ok {
+			testIntegerObject(t, evaluated, int64(integer))
+		} else {
+			testNullObject(t, evaluated)
+		}
+	}
+}
+
+func testEval(input string) object.Object {
+	lex := lexer.New(input)
+	par := parser.New(lex)
+	program := par.ParseProgram()
+	env := object.NewEnvironment()
+
+	return Eval(program, env)
+}
+
+func testIntegerObject(t testing.TB, obj object.Object, expected int64) bool {
+	t.Helper()
+
+	result, ok := obj.(*object.Integer)
+	if !ok {
+		t.Errorf("object is not Integer. got=%T (%+v)", obj, obj)
+		return false
+	}
+
+	if result.Value != expected {
+		t.Errorf("object has wrong value. got=%d, want=%d", result.Value, expected)
+		return false
+	}
+	return true
+}
+
+func testBooleanObject(t testing.TB, obj object.Object, expected bool) bool {
+	t.Helper()
+
+	result, ok := obj.(*object.Boolean)
+	if !ok {
+		t.Errorf("object is not Boolean. got=%T (%+v)", obj, obj)
+		return false
+	}
+
+	if result.Value != expected {
+		t.Errorf("object has wrong value. got=%t, want=%t", result.Value, expected)
+		return false
+	}
+	return true
+}
+
+func testNullObject(t testing.TB, obj object.Object) bool {
+	t.Helper()
+
+	if obj != NULL {
+		t.Errorf("object is not NULL. got=%T (%+v)", obj, obj)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,26 @@
+package lexer
+
+import (
+	"fmt"
+
+	"github.com/MichaelBo1/go_interpreter/token"
+)
+
+// Error describes malformed input found while lexing. The offending text is returned to
+// the parser as an ILLEGAL token so it can carry on past it.
+type Error struct {
+	Pos     token.Position
+	Message string
+}
+
+func (e Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos, e.Message)
+}
+
+func (l *Lexer) Errors() []Error {
+	return l.errors
+}
+
+func (l *Lexer) addError(pos token.Position, format string, a ...any) {
+	l.errors = append(l.errors, Error{Pos: pos, Message: fmt.Sprintf(format, a...)})
+}
@@ -1,97 +1,219 @@
 package lexer
 
 import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
 	"github.com/MichaelBo1/go_interpreter/token"
 )
 
 type Lexer struct {
 	input      string
-	currentPos int
-	nextPos    int
-	ch         byte
+	filename   string
+	currentPos int  // Byte offset of ch.
+	nextPos    int  // Byte offset of the character after ch.
+	ch         rune // The current character, decoded from UTF-8.
+
+	// Line and column of the current character, both 1-based. Columns count characters
+	// rather than bytes, so a multi-byte character only advances the column by one.
+	line   int
+	column int
+
+	errors []Error
+
+	emitComments bool
+
+	// One entry per `${` we are currently inside, innermost last.
+	interpolations []interpolation
 }
 
-func New(input string) *Lexer {
+type interpolation struct {
+	stringStart token.Position // Position of the opening quote of the enclosing string.
+	braceDepth  int            // Number of unclosed `{` inside the interpolation.
+}
+
+type stringTerminator int
+
+const (
+	terminatedByQuote stringTerminator = iota
+	terminatedByInterpolation
+	terminatedByEOF
+)
+
+// Option configures optional lexer behaviour.
+type Option func(*Lexer)
+
+// WithComments makes the lexer return comments as COMMENT tokens instead of skipping them,
+// for tools such as formatters that need to preserve them.
+func WithComments() Option {
+	return func(l *Lexer) {
+		l.emitComments = true
+	}
+}
+
+func New(input string, opts ...Option) *Lexer {
+	return NewWithFilename("", input, opts...)
+}
+
+// NewWithFilename is like New but records filename in the position of every token, so
+// errors can point back at the file the input was read from.
+func NewWithFilename(filename string, input string, opts ...Option) *Lexer {
 	lexer := &Lexer{
-		input: input,
+		input:    input,
+		filename: filename,
+		line:     1,
+	}
+	for _, opt := range opts {
+		opt(lexer)
 	}
 	lexer.readChar()
 	return lexer
 }
 
-// TODO: this doesn't support Unicode (& UTF-8), which would need to use runes and would also
-// need to work for multi-byte-length encodings.
+// readChar decodes the next UTF-8 character into ch. Invalid encodings decode as
+// utf8.RuneError one byte at a time, which the lexer then reports as UNKNOWN tokens.
 func (l *Lexer) readChar() {
+	// Once we've reached EOF there is nothing left to advance past, so stay put to keep the
+	// position of the EOF token stable however many times it is requested.
+	if l.nextPos > len(l.input) {
+		return
+	}
+
+	if l.ch == '\n' {
+		l.line++
+		l.column = 1
+	} else {
+		l.column++
+	}
+
+	size := 1
 	if l.nextPos >= len(l.input) {
 		l.ch = 0 // Signifier for EOF
 	} else {
-		l.ch = l.input[l.nextPos]
+		l.ch, size = utf8.DecodeRuneInString(l.input[l.nextPos:])
 	}
 
 	l.currentPos = l.nextPos
-	l.nextPos += 1
+	l.nextPos += size
 }
 
 func (l *Lexer) NextToken() token.Token {
-	var tok token.Token
-
 	l.eatWhitespace()
 
-	// TODO: extract the two-char token logic.
+	for l.atComment() {
+		start := l.position()
+		tok := l.readComment()
+		if l.emitComments {
+			tok.Pos = start
+			tok.End = l.position()
+			return tok
+		}
+		l.eatWhitespace()
+	}
+
+	start := l.position()
+	tok := l.scanToken()
+	tok.Pos = start
+	tok.End = l.position()
+
+	return tok
+}
+
+func (l *Lexer) scanToken() token.Token {
+	var tok token.Token
+
 	switch l.ch {
 	case '=':
-		if l.peek() == '=' {
-			ch := l.ch
-			l.readChar()
-			tok = token.NewToken(token.EQ, string(ch)+string(l.ch))
-		} else {
-			tok = token.NewToken(token.ASSIGN, string(l.ch))
-		}
+		tok = l.oneOrTwoCharToken('=', token.EQ, token.ASSIGN)
 	case '+':
-		tok = token.NewToken(token.PLUS, string(l.ch))
+		tok = l.oneOrTwoCharToken('=', token.PLUS_ASSIGN, token.PLUS)
 	case '-':
-		tok = token.NewToken(token.MINUS, string(l.ch))
+		tok = l.oneOrTwoCharToken('=', token.MINUS_ASSIGN, token.MINUS)
 	case '/':
-		tok = token.NewToken(token.SLASH, string(l.ch))
+		tok = l.oneOrTwoCharToken('=', token.SLASH_ASSIGN, token.SLASH)
 	case '!':
-		if l.peek() == '=' {
-			ch := l.ch
-			l.readChar()
-			tok = token.NewToken(token.NOT_EQ, string(ch)+string(l.ch))
-		} else {
-			tok = token.NewToken(token.BANG, string(l.ch))
-		}
+		tok = l.oneOrTwoCharToken('=', token.NOT_EQ, token.BANG)
 	case '*':
-		tok = token.NewToken(token.ASTERISK, string(l.ch))
+		tok = l.oneOrTwoCharToken('*', token.POWER, token.ASTERISK)
+		if tok.Type == token.ASTERISK {
+			tok = l.oneOrTwoCharToken('=', token.ASTERISK_ASSIGN, token.ASTERISK)
+		}
+	case '%':
+		tok = l.oneOrTwoCharToken('=', token.PERCENT_ASSIGN, token.PERCENT)
 	case '<':
-		if l.peek() == '=' {
-			ch := l.ch
-			l.readChar()
-			tok = token.NewToken(token.LESS_THAN_OR_EQ, string(ch)+string(l.ch))
-		} else {
-			tok = token.NewToken(token.LESS_THAN, string(l.ch))
+		tok = l.oneOrTwoCharToken('=', token.LESS_THAN_OR_EQ, token.LESS_THAN)
+		if tok.Type == token.LESS_THAN {
+			tok = l.oneOrTwoCharToken('<', token.SHIFT_LEFT, token.LESS_THAN)
 		}
 	case '>':
-		if l.peek() == '=' {
-			ch := l.ch
-			l.readChar()
-			tok = token.NewToken(token.GREATER_THAN_OR_EQ, string(ch)+string(l.ch))
-		} else {
-			tok = token.NewToken(token.GREATER_THAN, string(l.ch))
+		tok = l.oneOrTwoCharToken('=', token.GREATER_THAN_OR_EQ, token.GREATER_THAN)
+		if tok.Type == token.GREATER_THAN {
+			tok = l.oneOrTwoCharToken('>', token.SHIFT_RIGHT, token.GREATER_THAN)
 		}
+	case '&':
+		tok = l.oneOrTwoCharToken('&', token.AND, token.AMPERSAND)
+	case '|':
+		tok = l.oneOrTwoCharToken('|', token.OR, token.PIPE)
+	case '^':
+		tok = token.NewToken(token.CARET, string(l.ch))
+	case '~':
+		tok = token.NewToken(token.TILDE, string(l.ch))
 	case ',':
 		tok = token.NewToken(token.COMMA, string(l.ch))
 	case ';':
 		tok = token.NewToken(token.SEMICOLON, string(l.ch))
+	case ':':
+		tok = token.NewToken(token.COLON, string(l.ch))
+	case '.':
+		switch {
+		case isDigit(l.peek()):
+			return l.readNumber() // Reports the missing digit before the decimal point.
+		case l.peek() == '.':
+			l.readChar()
+			if l.peek() == '=' {
+				l.readChar()
+				tok = token.NewToken(token.DOTDOT_EQ, "..=")
+			} else {
+				tok = token.NewToken(token.DOTDOT, "..")
+			}
+		default:
+			tok = token.NewToken(token.UNKNOWN, string(l.ch))
+		}
 	case '(':
 		tok = token.NewToken(token.LPAREN, string(l.ch))
 	case ')':
 		tok = token.NewToken(token.RPAREN, string(l.ch))
+	case '[':
+		tok = token.NewToken(token.LBRACKET, string(l.ch))
+	case ']':
+		tok = token.NewToken(token.RBRACKET, string(l.ch))
+	case '"':
+		tok = l.readString(l.position(), token.STRING, token.STRING_START)
 	case '{':
+		if len(l.interpolations) > 0 {
+			l.interpolations[len(l.interpolations)-1].braceDepth++
+		}
 		tok = token.NewToken(token.LBRACE, string(l.ch))
 	case '}':
+		if n := len(l.interpolations); n > 0 {
+			if l.interpolations[n-1].braceDepth == 0 {
+				// This brace closes the interpolation, so carry on with the rest of the string.
+				start := l.interpolations[n-1].stringStart
+				l.interpolations = l.interpolations[:n-1]
+				tok = l.readString(start, token.STRING_END, token.STRING_MID)
+				break
+			}
+			l.interpolations[n-1].braceDepth--
+		}
 		tok = token.NewToken(token.RBRACE, string(l.ch))
 	case 0:
+		// Input that ends inside an interpolation never reaches the string's closing quote.
+		if len(l.interpolations) > 0 {
+			l.addError(l.interpolations[0].stringStart, "unterminated string literal")
+			l.interpolations = nil
+		}
 		tok = token.NewToken(token.EOF, "")
 	default:
 		if isLetter(l.ch) {
@@ -100,9 +222,7 @@ func (l *Lexer) NextToken() token.Token {
 			return tok // Early exit as `readIdentifier` calls readChar() and eats the input.
 		}
 		if isDigit(l.ch) {
-			tok.Type = token.INT
-			tok.Literal = l.readInt()
-			return tok
+			return l.readNumber()
 		}
 		tok = token.NewToken(token.UNKNOWN, string(l.ch))
 	}
@@ -111,20 +231,308 @@ func (l *Lexer) NextToken() token.Token {
 	return tok
 }
 
+// oneOrTwoCharToken returns a token of type two if the current character is followed by
+// second, consuming both, or a token of type one for the current character on its own.
+func (l *Lexer) oneOrTwoCharToken(second rune, two, one token.TokenType) token.Token {
+	if l.peek() != second {
+		return token.NewToken(one, string(l.ch))
+	}
+	ch := l.ch
+	l.readChar()
+	return token.NewToken(two, string(ch)+string(l.ch))
+}
+
+func (l *Lexer) position() token.Position {
+	return token.Position{
+		Filename: l.filename,
+		Offset:   l.currentPos,
+		Line:     l.line,
+		Column:   l.column,
+	}
+}
+
+func (l *Lexer) atComment() bool {
+	return l.ch == '/' && (l.peek() == '/' || l.peek() == '*')
+}
+
+// readComment consumes a `//` comment up to the end of the line, or a `/* */` comment,
+// which may contain further nested block comments. Unlike scanToken, it leaves the lexer
+// on the character after the comment.
+func (l *Lexer) readComment() token.Token {
+	start := l.position()
+	l.readChar()
+
+	if l.ch == '/' {
+		for l.ch != '\n' && l.currentPos < len(l.input) {
+			l.readChar()
+		}
+		return token.NewToken(token.COMMENT, l.input[start.Offset:l.currentPos])
+	}
+
+	l.readChar()
+	for depth := 1; depth > 0; {
+		switch {
+		case l.currentPos >= len(l.input):
+			l.addError(start, "unterminated block comment")
+			return token.NewToken(token.ILLEGAL, l.input[start.Offset:])
+		case l.ch == '*' && l.peek() == '/':
+			l.readChar()
+			depth--
+		case l.ch == '/' && l.peek() == '*':
+			l.readChar()
+			depth++
+		}
+		l.readChar()
+	}
+
+	return token.NewToken(token.COMMENT, l.input[start.Offset:l.currentPos])
+}
+
 func (l *Lexer) readIdentifier() string {
 	pos := l.currentPos
-	for isLetter(l.ch) {
+	for isIdentifierPart(l.ch) {
 		l.readChar()
 	}
 	return l.input[pos:l.currentPos]
 }
 
-func (l *Lexer) readInt() string {
-	pos := l.currentPos
-	for isDigit(l.ch) {
+// readString reads the part of a string literal that follows the current character, which
+// is either its opening quote or the `}` ending an interpolation. The token is of type
+// onQuote if the part runs to the closing quote and onInterpolation if it ends at a `${`.
+// Its literal holds the text with escape sequences decoded, and the lexer is left on the
+// final character of the token so the caller's readChar moves past it.
+//
+// A malformed string produces an ILLEGAL token holding the raw text, with the reasons
+// recorded as errors. stringStart locates the opening quote for reporting a missing close.
+func (l *Lexer) readString(stringStart token.Position, onQuote, onInterpolation token.TokenType) token.Token {
+	start := l.currentPos
+	value, terminator, ok := l.readStringPart()
+
+	switch terminator {
+	case terminatedByEOF:
+		l.addError(stringStart, "unterminated string literal")
+		ok = false
+	case terminatedByInterpolation:
+		// Track the interpolation even if this part was malformed, so the rest of the string
+		// is still lexed as part of it.
+		l.interpolations = append(l.interpolations, interpolation{stringStart: stringStart})
+	}
+
+	switch {
+	case !ok:
+		return token.NewToken(token.ILLEGAL, l.input[start:min(l.nextPos, len(l.input))])
+	case terminator == terminatedByInterpolation:
+		return token.NewToken(onInterpolation, value)
+	default:
+		return token.NewToken(onQuote, value)
+	}
+}
+
+// readStringPart decodes characters up to a closing quote, the `{` of a `${` or EOF.
+// ok is false if an invalid escape sequence was found.
+func (l *Lexer) readStringPart() (value string, terminator stringTerminator, ok bool) {
+	var result strings.Builder
+	ok = true
+
+	for {
 		l.readChar()
+
+		switch {
+		case l.currentPos >= len(l.input):
+			return result.String(), terminatedByEOF, false
+		case l.ch == '"':
+			return result.String(), terminatedByQuote, ok
+		case l.ch == '$' && l.peek() == '{':
+			l.readChar()
+			return result.String(), terminatedByInterpolation, ok
+		case l.ch == '\\':
+			if !l.readEscape(&result) {
+				ok = false
+			}
+		default:
+			result.WriteRune(l.ch)
+		}
+	}
+}
+
+// readEscape decodes the escape sequence starting at the current backslash into result.
+func (l *Lexer) readEscape(result *strings.Builder) bool {
+	escapePos := l.position()
+	l.readChar()
+
+	switch l.ch {
+	case 'n':
+		result.WriteByte('\n')
+	case 't':
+		result.WriteByte('\t')
+	case 'r':
+		result.WriteByte('\r')
+	case '"':
+		result.WriteByte('"')
+	case '\\':
+		result.WriteByte('\\')
+	case '$':
+		result.WriteByte('$')
+	case 'u':
+		return l.readUnicodeEscape(escapePos, result)
+	default:
+		if l.currentPos >= len(l.input) {
+			// Leave the unterminated string error to readString.
+			return false
+		}
+		l.addError(escapePos, "unknown escape sequence \\%c", l.ch)
+		return false
+	}
+	return true
+}
+
+// readUnicodeEscape decodes the `{XXXX}` part of a `\u{XXXX}` escape, where XXXX is 1 to 6
+// hex digits naming a Unicode code point.
+func (l *Lexer) readUnicodeEscape(escapePos token.Position, result *strings.Builder) bool {
+	if l.peek() != '{' {
+		l.addError(escapePos, "expected { after \\u")
+		return false
+	}
+	l.readChar()
+
+	digitsStart := l.nextPos
+	for isHexDigit(l.peek()) {
+		l.readChar()
+	}
+	digits := l.input[digitsStart:l.nextPos]
+
+	if l.peek() != '}' {
+		l.addError(escapePos, "unterminated \\u{...} escape")
+		return false
+	}
+	l.readChar()
+
+	if len(digits) == 0 || len(digits) > 6 {
+		l.addError(escapePos, "\\u{...} escape must have between 1 and 6 hex digits")
+		return false
+	}
+
+	codePoint, _ := strconv.ParseUint(digits, 16, 32)
+	if !utf8.ValidRune(rune(codePoint)) {
+		l.addError(escapePos, "\\u{%s} is not a valid Unicode code point", digits)
+		return false
+	}
+
+	result.WriteRune(rune(codePoint))
+	return true
+}
+
+// readNumber reads an INT such as `42`, `1_000_000`, `0xFF`, `0o17` or `0b1010`, or a FLOAT
+// such as `3.14`, `1e-9` or `2.5E3`. Underscores may separate digits, but not appear twice
+// in a row or at the end of the digits. A '.' is only part of the number if a digit follows
+// it, so `1.` is an INT followed by a '.'. A leading '.' as in `.5` is rejected rather than
+// read as a FLOAT.
+func (l *Lexer) readNumber() token.Token {
+	start := l.position()
+	tokenType := token.INT
+	ok := true
+
+	// Only the first problem with a number is reported, as later ones tend to be knock-on errors.
+	fail := func(pos token.Position, format string, a ...any) {
+		if ok {
+			l.addError(pos, format, a...)
+			ok = false
+		}
+	}
+
+	if l.ch == '0' {
+		if name, isBaseDigit, prefixed := basePrefix(l.peek()); prefixed {
+			l.readChar()
+			l.readChar()
+
+			if l.readDigits(isBaseDigit, fail) == 0 {
+				fail(start, "%s literal has no digits", name)
+			}
+			// Digits and letters straight after the literal are most likely meant to be part of it.
+			for isLetter(l.ch) || isDigit(l.ch) {
+				fail(l.position(), "invalid digit %q in %s literal", l.ch, name)
+				l.readChar()
+			}
+
+			return l.numberToken(start, tokenType, ok)
+		}
+	}
+
+	leadingDot := l.ch == '.'
+	l.readDigits(isDigit, fail)
+
+	if l.ch == '.' && isDigit(l.peek()) {
+		tokenType = token.FLOAT
+		l.readChar()
+		l.readDigits(isDigit, fail)
+	}
+
+	if l.ch == 'e' || l.ch == 'E' {
+		tokenType = token.FLOAT
+		l.readChar()
+		if l.ch == '+' || l.ch == '-' {
+			l.readChar()
+		}
+		if l.readDigits(isDigit, fail) == 0 {
+			fail(start, "exponent has no digits")
+		}
+	}
+
+	if leadingDot {
+		fail(start, "float literal must have a digit before the decimal point, e.g. 0.5")
+	}
+
+	return l.numberToken(start, tokenType, ok)
+}
+
+func (l *Lexer) numberToken(start token.Position, tokenType token.TokenType, ok bool) token.Token {
+	literal := l.input[start.Offset:l.currentPos]
+	if !ok {
+		return token.NewToken(token.ILLEGAL, literal)
+	}
+	return token.NewToken(tokenType, literal)
+}
+
+// readDigits reads a run of digits accepted by isValid, along with any underscores
+// separating them, and returns how many digits it read.
+func (l *Lexer) readDigits(isValid func(rune) bool, fail func(token.Position, string, ...any)) int {
+	count := 0
+	var lastUnderscore *token.Position
+
+	for {
+		switch {
+		case isValid(l.ch):
+			count++
+			lastUnderscore = nil
+		case l.ch == '_':
+			pos := l.position()
+			if lastUnderscore != nil {
+				fail(pos, "consecutive underscores in number literal")
+			}
+			lastUnderscore = &pos
+		default:
+			if lastUnderscore != nil {
+				fail(*lastUnderscore, "number literal cannot end with an underscore")
+			}
+			return count
+		}
+		l.readChar()
+	}
+}
+
+// basePrefix reports whether ch is the letter after the '0' of a prefixed integer literal,
+// returning a name for the base and the digits that are valid in it.
+func basePrefix(ch rune) (name string, isBaseDigit func(rune) bool, ok bool) {
+	switch ch {
+	case 'x', 'X':
+		return "hexadecimal", isHexDigit, true
+	case 'o', 'O':
+		return "octal", func(ch rune) bool { return '0' <= ch && ch <= '7' }, true
+	case 'b', 'B':
+		return "binary", func(ch rune) bool { return ch == '0' || ch == '1' }, true
+	default:
+		return "", nil, false
 	}
-	return l.input[pos:l.currentPos]
 }
 
 func (l *Lexer) eatWhitespace() {
@@ -133,19 +541,32 @@ func (l *Lexer) eatWhitespace() {
 	}
 }
 
-// Simplified version here since currently we are only dealing with ASCII chars.
-// See (TODO) above for supporting Unicode.
-func isLetter(ch byte) bool {
-	return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || ch == '_'
+// Identifiers start with any Unicode letter or an underscore, e.g. `größe`, `変数` or `x2`.
+func isLetter(ch rune) bool {
+	return ch == '_' || unicode.IsLetter(ch)
 }
 
-func isDigit(ch byte) bool {
+// After the first character, identifiers may also contain Unicode digits, combining marks
+// and connector punctuation, following XID_Continue from UAX #31. The marks are needed for
+// scripts such as Devanagari, where `नमस्ते` has vowel signs that aren't letters.
+func isIdentifierPart(ch rune) bool {
+	return isLetter(ch) || unicode.IsDigit(ch) || unicode.In(ch, unicode.Mn, unicode.Mc, unicode.Pc)
+}
+
+// Number literals are restricted to ASCII digits; other scripts' digits are only
+// accepted inside identifiers.
+func isDigit(ch rune) bool {
 	return '0' <= ch && ch <= '9'
 }
 
-func (l *Lexer) peek() byte {
+func isHexDigit(ch rune) bool {
+	return isDigit(ch) || 'a' <= ch && ch <= 'f' || 'A' <= ch && ch <= 'F'
+}
+
+func (l *Lexer) peek() rune {
 	if l.nextPos >= len(l.input) {
 		return 0
 	}
-	return l.input[l.nextPos]
+	ch, _ := utf8.DecodeRuneInString(l.input[l.nextPos:])
+	return ch
 }
@@ -16,7 +16,7 @@ func TestNextToken(t *testing.T) {
 	
 	let result = add(five, ten);
 	
-	!-/*5;
+	!-/ *5;
 	5 < 10 > 5;
 	
 	if (5 < 10) {
@@ -30,6 +30,7 @@ func TestNextToken(t *testing.T) {
 
 	5 >= 9;
 	6 <= 3;
+	[1, 2][0:1];
 	`
 
 	tests := []struct {
@@ -117,6 +118,17 @@ func TestNextToken(t *testing.T) {
 		{token.LESS_THAN_OR_EQ, "<="},
 		{token.INT, "3"},
 		{token.SEMICOLON, ";"},
+		{token.LBRACKET, "["},
+		{token.INT, "1"},
+		{token.COMMA, ","},
+		{token.INT, "2"},
+		{token.RBRACKET, "]"},
+		{token.LBRACKET, "["},
+		{token.INT, "0"},
+		{token.COLON, ":"},
+		{token.INT, "1"},
+		{token.RBRACKET, "]"},
+		{token.SEMICOLON, ";"},
 		{token.EOF, ""},
 	}
 
@@ -136,3 +148,660 @@ func TestNextToken(t *testing.T) {
 		}
 	}
 }
+
+func TestTokenPositions(t *testing.T) {
+	input := "let x = 5;\n  x + 10\n"
+
+	tests := []struct {
+		expectedType  token.TokenType
+		expectedStart token.Position
+		expectedEnd   token.Position
+	}{
+		{token.LET, token.Position{Filename: "test.mk", Offset: 0, Line: 1, Column: 1}, token.Position{Filename: "test.mk", Offset: 3, Line: 1, Column: 4}},
+		{token.IDENTIFIER, token.Position{Filename: "test.mk", Offset: 4, Line: 1, Column: 5}, token.Position{Filename: "test.mk", Offset: 5, Line: 1, Column: 6}},
+		{token.ASSIGN, token.Position{Filename: "test.mk", Offset: 6, Line: 1, Column: 7}, token.Position{Filename: "test.mk", Offset: 7, Line: 1, Column: 8}},
+		{token.INT, token.Position{Filename: "test.mk", Offset: 8, Line: 1, Column: 9}, token.Position{Filename: "test.mk", Offset: 9, Line: 1, Column: 10}},
+		{token.SEMICOLON, token.Position{Filename: "test.mk", Offset: 9, Line: 1, Column: 10}, token.Position{Filename: "test.mk", Offset: 10, Line: 1, Column: 11}},
+		{token.IDENTIFIER, token.Position{Filename: "test.mk", Offset: 13, Line: 2, Column: 3}, token.Position{Filename: "test.mk", Offset: 14, Line: 2, Column: 4}},
+		{token.PLUS, token.Position{Filename: "test.mk", Offset: 15, Line: 2, Column: 5}, token.Position{Filename: "test.mk", Offset: 16, Line: 2, Column: 6}},
+		{token.INT, token.Position{Filename: "test.mk", Offset: 17, Line: 2, Column: 7}, token.Position{Filename: "test.mk", Offset: 19, Line: 2, Column: 9}},
+		{token.EOF, token.Position{Filename: "test.mk", Offset: 20, Line: 3, Column: 1}, token.Position{Filename: "test.mk", Offset: 20, Line: 3, Column: 1}},
+		{token.EOF, token.Position{Filename: "test.mk", Offset: 20, Line: 3, Column: 1}, token.Position{Filename: "test.mk", Offset: 20, Line: 3, Column: 1}},
+	}
+
+	lexer := NewWithFilename("test.mk", input)
+
+	for i, test := range tests {
+		tok := lexer.NextToken()
+
+		if tok.Type != test.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, test.expectedType, tok.Type)
+		}
+
+		if tok.Pos != test.expectedStart {
+			t.Errorf("tests[%d] - start position wrong. expected=%+v, got=%+v", i, test.expectedStart, tok.Pos)
+		}
+
+		if tok.End != test.expectedEnd {
+			t.Errorf("tests[%d] - end position wrong. expected=%+v, got=%+v", i, test.expectedEnd, tok.End)
+		}
+	}
+}
+
+func TestUnicodeIdentifiers(t *testing.T) {
+	input := "let größe = 変数 + x2 + _ñ;\n€\nनमस्ते = a‿b"
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+		expectedOffset  int
+		expectedColumn  int
+	}{
+		{token.LET, "let", 0, 1},
+		{token.IDENTIFIER, "größe", 4, 5},
+		{token.ASSIGN, "=", 12, 11},
+		{token.IDENTIFIER, "変数", 14, 13},
+		{token.PLUS, "+", 21, 16},
+		{token.IDENTIFIER, "x2", 23, 18},
+		{token.PLUS, "+", 26, 21},
+		{token.IDENTIFIER, "_ñ", 28, 23},
+		{token.SEMICOLON, ";", 31, 25},
+		{token.UNKNOWN, "€", 33, 1},
+		{token.IDENTIFIER, "नमस्ते", 37, 1},
+		{token.ASSIGN, "=", 56, 8},
+		{token.IDENTIFIER, "a‿b", 58, 10},
+		{token.EOF, "", 63, 13},
+	}
+
+	lexer := New(input)
+
+	for i, test := range tests {
+		tok := lexer.NextToken()
+
+		if tok.Type != test.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, test.expectedType, tok.Type)
+		}
+
+		if tok.Literal != test.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, test.expectedLiteral, tok.Literal)
+		}
+
+		if tok.Pos.Offset != test.expectedOffset {
+			t.Errorf("tests[%d] - offset wrong. expected=%d, got=%d", i, test.expectedOffset, tok.Pos.Offset)
+		}
+
+		if tok.Pos.Column != test.expectedColumn {
+			t.Errorf("tests[%d] - column wrong. expected=%d, got=%d", i, test.expectedColumn, tok.Pos.Column)
+		}
+	}
+}
+
+func TestInvalidUTF8(t *testing.T) {
+	lexer := New("a \xff b")
+
+	expected := []token.TokenType{token.IDENTIFIER, token.UNKNOWN, token.IDENTIFIER, token.EOF}
+	for i, expectedType := range expected {
+		tok := lexer.NextToken()
+		if tok.Type != expectedType {
+			t.Fatalf("tokens[%d] - tokentype wrong. expected=%q, got=%q", i, expectedType, tok.Type)
+		}
+	}
+}
+
+func TestStringLiterals(t *testing.T) {
+	input := `"foobar" "foo bar" "" "a\nb\t\"c\"\\" "\u{48}\u{e9}\u{1F600}" "größe"`
+
+	expected := []string{"foobar", "foo bar", "", "a\nb\t\"c\"\\", "Hé😀", "größe"}
+
+	lexer := New(input)
+
+	for i, literal := range expected {
+		tok := lexer.NextToken()
+
+		if tok.Type != token.STRING {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, token.STRING, tok.Type)
+		}
+
+		if tok.Literal != literal {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, literal, tok.Literal)
+		}
+	}
+
+	if tok := lexer.NextToken(); tok.Type != token.EOF {
+		t.Fatalf("expected EOF, got=%q", tok.Type)
+	}
+
+	if len(lexer.Errors()) != 0 {
+		t.Errorf("unexpected lexer errors: %v", lexer.Errors())
+	}
+}
+
+func TestMalformedStringLiterals(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedLiteral string
+		expectedErrors  []string
+	}{
+		{"let s = \"abc", `"abc`, []string{"1:9: unterminated string literal"}},
+		{"\"line one\nline two", "\"line one\nline two", []string{"1:1: unterminated string literal"}},
+		{`"a\qb"`, `"a\qb"`, []string{`1:3: unknown escape sequence \q`}},
+		{`"\u{}"`, `"\u{}"`, []string{`1:2: \u{...} escape must have between 1 and 6 hex digits`}},
+		{`"\u{D800}"`, `"\u{D800}"`, []string{`1:2: \u{D800} is not a valid Unicode code point`}},
+		{`"\u41"`, `"\u41"`, []string{`1:2: expected { after \u`}},
+		{`"\u{41"`, `"\u{41"`, []string{`1:2: unterminated \u{...} escape`}},
+		{`"\`, `"\`, []string{"1:1: unterminated string literal"}},
+	}
+
+	for _, test := range tests {
+		lexer := New(test.input)
+
+		var tok token.Token
+		for tok = lexer.NextToken(); tok.Type != token.ILLEGAL && tok.Type != token.EOF; tok = lexer.NextToken() {
+		}
+
+		if tok.Type != token.ILLEGAL {
+			t.Errorf("expected ILLEGAL token for %q", test.input)
+			continue
+		}
+
+		if tok.Literal != test.expectedLiteral {
+			t.Errorf("literal wrong for %q. expected=%q, got=%q", test.input, test.expectedLiteral, tok.Literal)
+		}
+
+		errors := lexer.Errors()
+		if len(errors) != len(test.expectedErrors) {
+			t.Errorf("wrong number of errors for %q. expected=%d, got=%d (%v)", test.input, len(test.expectedErrors), len(errors), errors)
+			continue
+		}
+
+		for i, expected := range test.expectedErrors {
+			if errors[i].Error() != expected {
+				t.Errorf("errors[%d] wrong for %q. expected=%q, got=%q", i, test.input, expected, errors[i].Error())
+			}
+		}
+	}
+}
+
+func TestInterpolatedStrings(t *testing.T) {
+	input := `"total: ${a + b}!" "${x}${ {y: "${z}"} }" "\${not}"`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.STRING_START, "total: "},
+		{token.IDENTIFIER, "a"},
+		{token.PLUS, "+"},
+		{token.IDENTIFIER, "b"},
+		{token.STRING_END, "!"},
+		{token.STRING_START, ""},
+		{token.IDENTIFIER, "x"},
+		{token.STRING_MID, ""},
+		{token.LBRACE, "{"},
+		{token.IDENTIFIER, "y"},
+		{token.COLON, ":"},
+		{token.STRING_START, ""},
+		{token.IDENTIFIER, "z"},
+		{token.STRING_END, ""},
+		{token.RBRACE, "}"},
+		{token.STRING_END, ""},
+		{token.STRING, "${not}"},
+		{token.EOF, ""},
+	}
+
+	lexer := New(input)
+
+	for i, test := range tests {
+		tok := lexer.NextToken()
+
+		if tok.Type != test.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, test.expectedType, tok.Type)
+		}
+
+		if tok.Literal != test.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, test.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestUnterminatedInterpolatedString(t *testing.T) {
+	inputs := []string{
+		`let s = "a ${b} c`,
+		`let s = "a ${b`,
+	}
+
+	for _, input := range inputs {
+		lexer := New(input)
+
+		for tok := lexer.NextToken(); tok.Type != token.EOF; tok = lexer.NextToken() {
+		}
+
+		errors := lexer.Errors()
+		if len(errors) != 1 || errors[0].Error() != "1:9: unterminated string literal" {
+			t.Errorf("unexpected errors for %q: %v", input, errors)
+		}
+	}
+}
+
+func TestComments(t *testing.T) {
+	input := `// leading comment
+let x = 1; // trailing comment
+/* block
+   comment */ let y = x / 2;
+/* outer /* nested */ still comment */ y
+"// not a comment"
+//`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.LET, "let"},
+		{token.IDENTIFIER, "x"},
+		{token.ASSIGN, "="},
+		{token.INT, "1"},
+		{token.SEMICOLON, ";"},
+		{token.LET, "let"},
+		{token.IDENTIFIER, "y"},
+		{token.ASSIGN, "="},
+		{token.IDENTIFIER, "x"},
+		{token.SLASH, "/"},
+		{token.INT, "2"},
+		{token.SEMICOLON, ";"},
+		{token.IDENTIFIER, "y"},
+		{token.STRING, "// not a comment"},
+		{token.EOF, ""},
+	}
+
+	lexer := New(input)
+
+	for i, test := range tests {
+		tok := lexer.NextToken()
+
+		if tok.Type != test.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, test.expectedType, tok.Type)
+		}
+
+		if tok.Literal != test.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, test.expectedLiteral, tok.Literal)
+		}
+	}
+
+	if len(lexer.Errors()) != 0 {
+		t.Errorf("unexpected lexer errors: %v", lexer.Errors())
+	}
+}
+
+func TestEmittingComments(t *testing.T) {
+	input := "x // note\n/* a /* b */ */ y"
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+		expectedStart   string
+		expectedEnd     string
+	}{
+		{token.IDENTIFIER, "x", "1:1", "1:2"},
+		{token.COMMENT, "// note", "1:3", "1:10"},
+		{token.COMMENT, "/* a /* b */ */", "2:1", "2:16"},
+		{token.IDENTIFIER, "y", "2:17", "2:18"},
+		{token.EOF, "", "2:18", "2:18"},
+	}
+
+	lexer := New(input, WithComments())
+
+	for i, test := range tests {
+		tok := lexer.NextToken()
+
+		if tok.Type != test.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, test.expectedType, tok.Type)
+		}
+
+		if tok.Literal != test.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, test.expectedLiteral, tok.Literal)
+		}
+
+		if tok.Pos.String() != test.expectedStart || tok.End.String() != test.expectedEnd {
+			t.Errorf("tests[%d] - span wrong. expected=%s-%s, got=%s-%s",
+				i, test.expectedStart, test.expectedEnd, tok.Pos, tok.End)
+		}
+	}
+}
+
+func TestLogicalOperators(t *testing.T) {
+	input := "a && b || !c & d | e"
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.IDENTIFIER, "a"},
+		{token.AND, "&&"},
+		{token.IDENTIFIER, "b"},
+		{token.OR, "||"},
+		{token.BANG, "!"},
+		{token.IDENTIFIER, "c"},
+		{token.AMPERSAND, "&"},
+		{token.IDENTIFIER, "d"},
+		{token.PIPE, "|"},
+		{token.IDENTIFIER, "e"},
+		{token.EOF, ""},
+	}
+
+	lexer := New(input)
+
+	for i, test := range tests {
+		tok := lexer.NextToken()
+
+		if tok.Type != test.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, test.expectedType, tok.Type)
+		}
+
+		if tok.Literal != test.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, test.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestArithmeticAndBitwiseOperators(t *testing.T) {
+	input := "a % b ** c * d; ~e ^ f << 2 >> 1 <= g >= h < i > j &k|l"
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.IDENTIFIER, "a"},
+		{token.PERCENT, "%"},
+		{token.IDENTIFIER, "b"},
+		{token.POWER, "**"},
+		{token.IDENTIFIER, "c"},
+		{token.ASTERISK, "*"},
+		{token.IDENTIFIER, "d"},
+		{token.SEMICOLON, ";"},
+		{token.TILDE, "~"},
+		{token.IDENTIFIER, "e"},
+		{token.CARET, "^"},
+		{token.IDENTIFIER, "f"},
+		{token.SHIFT_LEFT, "<<"},
+		{token.INT, "2"},
+		{token.SHIFT_RIGHT, ">>"},
+		{token.INT, "1"},
+		{token.LESS_THAN_OR_EQ, "<="},
+		{token.IDENTIFIER, "g"},
+		{token.GREATER_THAN_OR_EQ, ">="},
+		{token.IDENTIFIER, "h"},
+		{token.LESS_THAN, "<"},
+		{token.IDENTIFIER, "i"},
+		{token.GREATER_THAN, ">"},
+		{token.IDENTIFIER, "j"},
+		{token.AMPERSAND, "&"},
+		{token.IDENTIFIER, "k"},
+		{token.PIPE, "|"},
+		{token.IDENTIFIER, "l"},
+		{token.EOF, ""},
+	}
+
+	lexer := New(input)
+
+	for i, test := range tests {
+		tok := lexer.NextToken()
+
+		if tok.Type != test.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, test.expectedType, tok.Type)
+		}
+
+		if tok.Literal != test.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, test.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestAssignmentOperators(t *testing.T) {
+	input := "x = 1; x += 2; x -= 3; x *= 4; x /= 5; x %= 6; x ** 2 * 3 /=// note\n== -"
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.IDENTIFIER, "x"},
+		{token.ASSIGN, "="},
+		{token.INT, "1"},
+		{token.SEMICOLON, ";"},
+		{token.IDENTIFIER, "x"},
+		{token.PLUS_ASSIGN, "+="},
+		{token.INT, "2"},
+		{token.SEMICOLON, ";"},
+		{token.IDENTIFIER, "x"},
+		{token.MINUS_ASSIGN, "-="},
+		{token.INT, "3"},
+		{token.SEMICOLON, ";"},
+		{token.IDENTIFIER, "x"},
+		{token.ASTERISK_ASSIGN, "*="},
+		{token.INT, "4"},
+		{token.SEMICOLON, ";"},
+		{token.IDENTIFIER, "x"},
+		{token.SLASH_ASSIGN, "/="},
+		{token.INT, "5"},
+		{token.SEMICOLON, ";"},
+		{token.IDENTIFIER, "x"},
+		{token.PERCENT_ASSIGN, "%="},
+		{token.INT, "6"},
+		{token.SEMICOLON, ";"},
+		{token.IDENTIFIER, "x"},
+		{token.POWER, "**"},
+		{token.INT, "2"},
+		{token.ASTERISK, "*"},
+		{token.INT, "3"},
+		{token.SLASH_ASSIGN, "/="},
+		{token.EQ, "=="},
+		{token.MINUS, "-"},
+		{token.EOF, ""},
+	}
+
+	lexer := New(input)
+
+	for i, test := range tests {
+		tok := lexer.NextToken()
+
+		if tok.Type != test.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, test.expectedType, tok.Type)
+		}
+
+		if tok.Literal != test.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, test.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestUnterminatedBlockComment(t *testing.T) {
+	lexer := New("let x = 1;\n  /* outer /* inner */ never closed")
+
+	var tok token.Token
+	for tok = lexer.NextToken(); tok.Type != token.EOF; tok = lexer.NextToken() {
+	}
+
+	errors := lexer.Errors()
+	if len(errors) != 1 || errors[0].Error() != "2:3: unterminated block comment" {
+		t.Errorf("unexpected errors: %v", errors)
+	}
+}
+
+func TestNumberLiterals(t *testing.T) {
+	input := "3.14 42 1e-9 2.5E3 6e+2 1. x 0.5"
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.FLOAT, "3.14"},
+		{token.INT, "42"},
+		{token.FLOAT, "1e-9"},
+		{token.FLOAT, "2.5E3"},
+		{token.FLOAT, "6e+2"},
+		{token.INT, "1"},
+		{token.UNKNOWN, "."},
+		{token.IDENTIFIER, "x"},
+		{token.FLOAT, "0.5"},
+		{token.EOF, ""},
+	}
+
+	lexer := New(input)
+
+	for i, test := range tests {
+		tok := lexer.NextToken()
+
+		if tok.Type != test.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, test.expectedType, tok.Type)
+		}
+
+		if tok.Literal != test.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, test.expectedLiteral, tok.Literal)
+		}
+	}
+
+	if len(lexer.Errors()) != 0 {
+		t.Errorf("unexpected lexer errors: %v", lexer.Errors())
+	}
+}
+
+func TestRangeOperators(t *testing.T) {
+	input := "for (i in 0..n) 1..=10 1.5..2 a . b"
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.FOR, "for"},
+		{token.LPAREN, "("},
+		{token.IDENTIFIER, "i"},
+		{token.IN, "in"},
+		{token.INT, "0"},
+		{token.DOTDOT, ".."},
+		{token.IDENTIFIER, "n"},
+		{token.RPAREN, ")"},
+		{token.INT, "1"},
+		{token.DOTDOT_EQ, "..="},
+		{token.INT, "10"},
+		{token.FLOAT, "1.5"},
+		{token.DOTDOT, ".."},
+		{token.INT, "2"},
+		{token.IDENTIFIER, "a"},
+		{token.UNKNOWN, "."},
+		{token.IDENTIFIER, "b"},
+		{token.EOF, ""},
+	}
+
+	lexer := New(input)
+
+	for i, test := range tests {
+		tok := lexer.NextToken()
+
+		if tok.Type != test.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, test.expectedType, tok.Type)
+		}
+
+		if tok.Literal != test.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, test.expectedLiteral, tok.Literal)
+		}
+	}
+
+	if len(lexer.Errors()) != 0 {
+		t.Errorf("unexpected lexer errors: %v", lexer.Errors())
+	}
+}
+
+func TestMalformedFloatLiterals(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedLiteral string
+		expectedError   string
+	}{
+		{".5", ".5", "1:1: float literal must have a digit before the decimal point, e.g. 0.5"},
+		{"x = 1e;", "1e", "1:5: exponent has no digits"},
+		{"2.5e-", "2.5e-", "1:1: exponent has no digits"},
+	}
+
+	for _, test := range tests {
+		lexer := New(test.input)
+
+		var tok token.Token
+		for tok = lexer.NextToken(); tok.Type != token.ILLEGAL && tok.Type != token.EOF; tok = lexer.NextToken() {
+		}
+
+		if tok.Literal != test.expectedLiteral {
+			t.Errorf("literal wrong for %q. expected=%q, got=%q", test.input, test.expectedLiteral, tok.Literal)
+		}
+
+		errors := lexer.Errors()
+		if len(errors) != 1 || errors[0].Error() != test.expectedError {
+			t.Errorf("unexpected errors for %q: %v", test.input, errors)
+		}
+	}
+}
+
+func TestPrefixedAndSeparatedIntegers(t *testing.T) {
+	input := "0xFF 0XdeadBEEF 0o17 0b1010 1_000_000 0x_FF_FF 1_000.5e1_0 007"
+
+	expected := []string{"0xFF", "0XdeadBEEF", "0o17", "0b1010", "1_000_000", "0x_FF_FF", "1_000.5e1_0", "007"}
+
+	lexer := New(input)
+
+	for i, literal := range expected {
+		tok := lexer.NextToken()
+
+		if tok.Type != token.INT && tok.Type != token.FLOAT {
+			t.Fatalf("tests[%d] - tokentype wrong. expected a number, got=%q (%q)", i, tok.Type, tok.Literal)
+		}
+
+		if tok.Literal != literal {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, literal, tok.Literal)
+		}
+	}
+
+	if tok := lexer.NextToken(); tok.Type != token.EOF {
+		t.Fatalf("expected EOF, got=%q", tok.Type)
+	}
+
+	if len(lexer.Errors()) != 0 {
+		t.Errorf("unexpected lexer errors: %v", lexer.Errors())
+	}
+}
+
+func TestMalformedIntegers(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedLiteral string
+		expectedError   string
+	}{
+		{"0x", "0x", "1:1: hexadecimal literal has no digits"},
+		{"x = 0b;", "0b", "1:5: binary literal has no digits"},
+		{"1__0", "1__0", "1:3: consecutive underscores in number literal"},
+		{"10_", "10_", "1:3: number literal cannot end with an underscore"},
+		{"0b102", "0b102", "1:5: invalid digit '2' in binary literal"},
+		{"0o78", "0o78", "1:4: invalid digit '8' in octal literal"},
+		{"0xFG1", "0xFG1", "1:4: invalid digit 'G' in hexadecimal literal"},
+		{"0x_", "0x_", "1:3: number literal cannot end with an underscore"},
+	}
+
+	for _, test := range tests {
+		lexer := New(test.input)
+
+		var tok token.Token
+		for tok = lexer.NextToken(); tok.Type != token.ILLEGAL && tok.Type != token.EOF; tok = lexer.NextToken() {
+		}
+
+		if tok.Type != token.ILLEGAL {
+			t.Errorf("expected ILLEGAL token for %q", test.input)
+			continue
+		}
+
+		if tok.Literal != test.expectedLiteral {
+			t.Errorf("literal wrong for %q. expected=%q, got=%q", test.input, test.expectedLiteral, tok.Literal)
+		}
+
+		errors := lexer.Errors()
+		if len(errors) != 1 || errors[0].Error() != test.expectedError {
+			t.Errorf("unexpected errors for %q: %v", test.input, errors)
+		}
+	}
+}
@@ -5,6 +5,11 @@ import (
 	"os"
 	"os/user"
 
+	"github.com/MichaelBo1/go_interpreter/diagnostics"
+	"github.com/MichaelBo1/go_interpreter/evaluator"
+	"github.com/MichaelBo1/go_interpreter/lexer"
+	"github.com/MichaelBo1/go_interpreter/object"
+	"github.com/MichaelBo1/go_interpreter/parser"
 	"github.com/MichaelBo1/go_interpreter/repl"
 )
 
@@ -15,9 +20,39 @@ func check(err error) {
 }
 
 func main() {
+	if len(os.Args) > 1 {
+		os.Exit(runFile(os.Args[1]))
+	}
+
 	user, err := user.Current()
 	check(err)
 
 	fmt.Printf("Hello %s, This is the Monkey programming language.\n", user.Username)
 	repl.Run(os.Stdin, os.Stdout)
 }
+
+// runFile evaluates the script at path and returns the process exit code.
+func runFile(path string) int {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	lex := lexer.NewWithFilename(path, string(source))
+	par := parser.New(lex)
+
+	program := par.ParseProgram()
+	if len(par.Errors()) != 0 {
+		diagnostics.Fprint(os.Stderr, string(source), diagnostics.FromParseErrors(par.Errors()))
+		return 1
+	}
+
+	evaluated := evaluator.Eval(program, object.NewEnvironment())
+	if errObj, ok := evaluated.(*object.Error); ok {
+		fmt.Fprintln(os.Stderr, errObj.Inspect())
+		return 1
+	}
+
+	return 0
+}
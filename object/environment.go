@@ -0,0 +1,74 @@
+package object
+
+// Environment maps identifiers to the values they are bound to. Each function call and
+// block gets its own Environment whose outer points at the enclosing scope, so lookups
+// walk outwards until a binding is found.
+type Environment struct {
+	store     map[string]Object
+	constants map[string]bool
+	outer     *Environment
+}
+
+func NewEnvironment() *Environment {
+	return &Environment{store: make(map[string]Object), constants: make(map[string]bool)}
+}
+
+func NewEnclosedEnvironment(outer *Environment) *Environment {
+	env := NewEnvironment()
+	env.outer = outer
+	return env
+}
+
+func (e *Environment) Get(name string) (Object, bool) {
+	obj, ok := e.store[name]
+	if !ok && e.outer != nil {
+		obj, ok = e.outer.Get(name)
+	}
+	return obj, ok
+}
+
+// Assign rebinds name in the innermost scope that already binds it, reporting whether it found one.
+func (e *Environment) Assign(name string, val Object) bool {
+	if _, ok := e.store[name]; ok {
+		e.store[name] = val
+		return true
+	}
+	if e.outer != nil {
+		return e.outer.Assign(name, val)
+	}
+	return false
+}
+
+// Set always binds in the current scope, shadowing any binding of the same name further out.
+func (e *Environment) Set(name string, val Object) Object {
+	e.store[name] = val
+	delete(e.constants, name)
+	return val
+}
+
+// SetConst binds name in the current scope like Set, but marks the binding as constant.
+func (e *Environment) SetConst(name string, val Object) Object {
+	e.store[name] = val
+	e.constants[name] = true
+	return val
+}
+
+// IsConst reports whether the innermost binding of name is constant.
+func (e *Environment) IsConst(name string) bool {
+	if _, ok := e.store[name]; ok {
+		return e.constants[name]
+	}
+	if e.outer != nil {
+		return e.outer.IsConst(name)
+	}
+	return false
+}
+
+// Declared reports whether name is bound in the current scope itself, and if so whether
+// that binding is constant.
+func (e *Environment) Declared(name string) (constant bool, ok bool) {
+	if _, ok := e.store[name]; !ok {
+		return false, false
+	}
+	return e.constants[name], true
+}
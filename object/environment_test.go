@@ -0,0 +1,89 @@
+package object
+
+import "testing"
+
+func TestEnvironmentChaining(t *testing.T) {
+	outer := NewEnvironment()
+	outer.Set("a", &Integer{Value: 1})
+	outer.Set("b", &Integer{Value: 2})
+
+	inner := NewEnclosedEnvironment(outer)
+	inner.Set("b", &Integer{Value: 20})
+
+	if val, ok := inner.Get("a"); !ok || val.(*Integer).Value != 1 {
+		t.Errorf("inner did not resolve a from outer scope. got=%v", val)
+	}
+
+	if val, ok := inner.Get("b"); !ok || val.(*Integer).Value != 20 {
+		t.Errorf("inner b not shadowed. got=%v", val)
+	}
+
+	if val, ok := outer.Get("b"); !ok || val.(*Integer).Value != 2 {
+		t.Errorf("outer b was modified by inner Set. got=%v", val)
+	}
+
+	if _, ok := inner.Get("c"); ok {
+		t.Errorf("expected c to be unbound")
+	}
+}
+
+func TestEnvironmentAssign(t *testing.T) {
+	outer := NewEnvironment()
+	outer.Set("a", &Integer{Value: 1})
+	outer.Set("b", &Integer{Value: 2})
+
+	inner := NewEnclosedEnvironment(outer)
+	inner.Set("b", &Integer{Value: 20})
+
+	if !inner.Assign("a", &Integer{Value: 10}) {
+		t.Fatalf("expected a to be assignable from inner scope")
+	}
+	if val, _ := outer.Get("a"); val.(*Integer).Value != 10 {
+		t.Errorf("assignment did not reach outer a. got=%v", val)
+	}
+
+	if !inner.Assign("b", &Integer{Value: 30}) {
+		t.Fatalf("expected b to be assignable")
+	}
+	if val, _ := outer.Get("b"); val.(*Integer).Value != 2 {
+		t.Errorf("assignment to shadowed b changed outer b. got=%v", val)
+	}
+
+	if inner.Assign("c", &Integer{Value: 1}) {
+		t.Errorf("expected assignment to unbound c to fail")
+	}
+	if _, ok := inner.Get("c"); ok {
+		t.Errorf("failed assignment bound c")
+	}
+}
+
+func TestEnvironmentConstants(t *testing.T) {
+	outer := NewEnvironment()
+	outer.SetConst("a", &Integer{Value: 1})
+	outer.Set("b", &Integer{Value: 2})
+
+	inner := NewEnclosedEnvironment(outer)
+	inner.Set("c", &Integer{Value: 3})
+
+	if !inner.IsConst("a") {
+		t.Errorf("expected a to be constant from inner scope")
+	}
+	if inner.IsConst("b") || inner.IsConst("c") || inner.IsConst("d") {
+		t.Errorf("expected only a to be constant")
+	}
+
+	if _, ok := inner.Declared("a"); ok {
+		t.Errorf("expected a not to be declared in inner scope itself")
+	}
+	if constant, ok := outer.Declared("a"); !ok || !constant {
+		t.Errorf("expected a to be declared as a constant in outer scope")
+	}
+
+	inner.Set("a", &Integer{Value: 10})
+	if inner.IsConst("a") {
+		t.Errorf("expected inner a to shadow the outer constant")
+	}
+	if !outer.IsConst("a") {
+		t.Errorf("shadowing a changed the outer constant")
+	}
+}
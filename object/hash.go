@@ -0,0 +1,97 @@
+package object
+
+import (
+	"bytes"
+	"strings"
+)
+
+// HashKey identifies a hashable value exactly, so two keys are equal only if their values
+// are. Keys of different types never compare equal, so the integer 1 and the boolean true
+// are distinct keys. Text holds a string key's contents; Value holds the other kinds.
+type HashKey struct {
+	Type  ObjectType
+	Value uint64
+	Text  string
+}
+
+// Hashable is implemented by objects that can be used as hash keys.
+type Hashable interface {
+	Object
+	HashKey() HashKey
+}
+
+func (i *Integer) HashKey() HashKey {
+	return HashKey{Type: i.Type(), Value: uint64(i.Value)}
+}
+
+func (b *Boolean) HashKey() HashKey {
+	var value uint64
+	if b.Value {
+		value = 1
+	}
+	return HashKey{Type: b.Type(), Value: value}
+}
+
+// Strings are keyed by their contents rather than a hash of them, so that two different
+// strings can never collide and overwrite each other's entry.
+func (s *String) HashKey() HashKey {
+	return HashKey{Type: s.Type(), Text: s.Value}
+}
+
+type HashPair struct {
+	Key   Object
+	Value Object
+}
+
+// Hash remembers the order its keys were first inserted in, so that printing and
+// iterating over it is deterministic.
+type Hash struct {
+	Pairs map[HashKey]HashPair
+	order []HashKey
+}
+
+func NewHash() *Hash {
+	return &Hash{Pairs: make(map[HashKey]HashPair)}
+}
+
+func (h *Hash) Type() ObjectType { return HASH_OBJ }
+func (h *Hash) Inspect() string {
+	var result bytes.Buffer
+
+	pairs := []string{}
+	for _, pair := range h.OrderedPairs() {
+		pairs = append(pairs, inspectNested(pair.Key)+": "+inspectNested(pair.Value))
+	}
+
+	result.WriteString("{")
+	result.WriteString(strings.Join(pairs, ", "))
+	result.WriteString("}")
+
+	return result.String()
+}
+
+func (h *Hash) Get(key Hashable) (Object, bool) {
+	pair, ok := h.Pairs[key.HashKey()]
+	if !ok {
+		return nil, false
+	}
+	return pair.Value, true
+}
+
+// Set adds or replaces the value for key. Replacing a value keeps the key's original position.
+func (h *Hash) Set(key Hashable, value Object) {
+	hashKey := key.HashKey()
+	if _, ok := h.Pairs[hashKey]; !ok {
+		h.order = append(h.order, hashKey)
+	}
+	h.Pairs[hashKey] = HashPair{Key: key, Value: value}
+}
+
+// OrderedPairs returns the pairs in insertion order.
+func (h *Hash) OrderedPairs() []HashPair {
+	pairs := make([]HashPair, 0, len(h.order))
+	for _, key := range h.order {
+		pairs = append(pairs, h.Pairs[key])
+	}
+	return pairs
+}
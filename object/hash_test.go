@@ -0,0 +1,83 @@
+package object
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStringHashKey(t *testing.T) {
+	hello1 := &String{Value: "Hello World"}
+	hello2 := &String{Value: "Hello World"}
+	diff1 := &String{Value: "My name is johnny"}
+	diff2 := &String{Value: "My name is johnny"}
+
+	if hello1.HashKey() != hello2.HashKey() {
+		t.Errorf("strings with same content have different hash keys")
+	}
+
+	if diff1.HashKey() != diff2.HashKey() {
+		t.Errorf("strings with same content have different hash keys")
+	}
+
+	if hello1.HashKey() == diff1.HashKey() {
+		t.Errorf("strings with different content have same hash keys")
+	}
+}
+
+func TestStringHashKeysNeverCollide(t *testing.T) {
+	hash := NewHash()
+	for i := range 1000 {
+		hash.Set(&String{Value: strings.Repeat("x", i)}, &Integer{Value: int64(i)})
+	}
+
+	if len(hash.Pairs) != 1000 {
+		t.Fatalf("distinct strings shared an entry. got=%d pairs", len(hash.Pairs))
+	}
+
+	for i := range 1000 {
+		value, ok := hash.Get(&String{Value: strings.Repeat("x", i)})
+		if !ok || value.(*Integer).Value != int64(i) {
+			t.Errorf("wrong value for key of length %d. got=%v", i, value)
+		}
+	}
+}
+
+func TestHashKeysAreTyped(t *testing.T) {
+	one := &Integer{Value: 1}
+	yes := &Boolean{Value: true}
+
+	if one.HashKey() == yes.HashKey() {
+		t.Errorf("integer 1 and boolean true have the same hash key")
+	}
+}
+
+func TestHashKeepsInsertionOrder(t *testing.T) {
+	hash := NewHash()
+	hash.Set(&String{Value: "b"}, &Integer{Value: 1})
+	hash.Set(&Integer{Value: 3}, &Integer{Value: 2})
+	hash.Set(&Boolean{Value: false}, &Integer{Value: 3})
+	hash.Set(&String{Value: "b"}, &Integer{Value: 4})
+
+	if hash.Inspect() != `{"b": 4, 3: 2, false: 3}` {
+		t.Errorf("hash.Inspect() wrong. got=%q", hash.Inspect())
+	}
+
+	value, ok := hash.Get(&Integer{Value: 3})
+	if !ok || value.(*Integer).Value != 2 {
+		t.Errorf("hash.Get(3) wrong. got=%v", value)
+	}
+
+	if _, ok := hash.Get(&String{Value: "missing"}); ok {
+		t.Errorf("expected missing key to be absent")
+	}
+}
+
+func TestInspectQuotesNestedStrings(t *testing.T) {
+	hash := NewHash()
+	hash.Set(&String{Value: "1"}, &Integer{Value: 1})
+	hash.Set(&Integer{Value: 1}, &Array{Elements: []Object{&String{Value: `say "hi"`}}})
+
+	if hash.Inspect() != `{"1": 1, 1: ["say \"hi\""]}` {
+		t.Errorf("hash.Inspect() wrong. got=%q", hash.Inspect())
+	}
+}
@@ -0,0 +1,20 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/MichaelBo1/go_interpreter/token"
+)
+
+// ParseError describes a single syntax error found while parsing, or a misuse of a name
+// found by the resolver afterwards.
+type ParseError struct {
+	Pos      token.Position
+	Expected token.TokenType // The token the parser wanted, or UNKNOWN if it wasn't after a particular one.
+	Found    token.Token     // The token the parser actually found at Pos.
+	Message  string
+}
+
+func (e ParseError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos, e.Message)
+}
@@ -2,7 +2,9 @@ package parser
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/MichaelBo1/go_interpreter/ast"
 	"github.com/MichaelBo1/go_interpreter/lexer"
@@ -14,14 +16,66 @@ type OperatorPrecedence int
 const (
 	_ OperatorPrecedence = iota
 	LOWEST
+	ASSIGN      // = or += etc.
+	LOGICAL_OR  // ||
+	LOGICAL_AND // &&
 	EQUALS      // ==
 	LESSGREATER // < or <= or > or >=
+	RANGE       // .. or ..=
+	BITWISE_OR  // |
+	BITWISE_XOR // ^
+	BITWISE_AND // &
+	SHIFT       // << or >>
 	SUM         // +
-	PRODUCT     // *
-	PREFIX      // -x or !x
+	PRODUCT     // * or / or %
+	PREFIX      // -x or !x or ~x
+	POWER       // **
 	CALL        // function(x)
+	INDEX       // array[index]
 )
 
+// RANGE sits between the comparisons and the other operators, so `0..n - 1` needs no
+// parentheses and `i < 0..n` is a type error rather than a range of booleans.
+//
+// The bitwise operators bind tighter than the comparisons, so `flags & MASK == 0` tests the
+// masked bits rather than and-ing flags with a boolean.
+//
+// POWER binds tighter than PREFIX, so a unary operator on its left applies to the whole power:
+// `-2 ** 2` is `-(2 ** 2)`. Its right operand is parsed as a fresh unary expression, so
+// `2 ** -1` also works. See parsePowerExpression for its associativity.
+
+var precedences = map[token.TokenType]OperatorPrecedence{
+	token.ASSIGN:             ASSIGN,
+	token.PLUS_ASSIGN:        ASSIGN,
+	token.MINUS_ASSIGN:       ASSIGN,
+	token.ASTERISK_ASSIGN:    ASSIGN,
+	token.SLASH_ASSIGN:       ASSIGN,
+	token.PERCENT_ASSIGN:     ASSIGN,
+	token.OR:                 LOGICAL_OR,
+	token.AND:                LOGICAL_AND,
+	token.EQ:                 EQUALS,
+	token.NOT_EQ:             EQUALS,
+	token.LESS_THAN:          LESSGREATER,
+	token.LESS_THAN_OR_EQ:    LESSGREATER,
+	token.GREATER_THAN:       LESSGREATER,
+	token.GREATER_THAN_OR_EQ: LESSGREATER,
+	token.PLUS:               SUM,
+	token.MINUS:              SUM,
+	token.DOTDOT:             RANGE,
+	token.DOTDOT_EQ:          RANGE,
+	token.PIPE:               BITWISE_OR,
+	token.CARET:              BITWISE_XOR,
+	token.AMPERSAND:          BITWISE_AND,
+	token.SHIFT_LEFT:         SHIFT,
+	token.SHIFT_RIGHT:        SHIFT,
+	token.SLASH:              PRODUCT,
+	token.ASTERISK:           PRODUCT,
+	token.PERCENT:            PRODUCT,
+	token.POWER:              POWER,
+	token.LPAREN:             CALL,
+	token.LBRACKET:           INDEX,
+}
+
 type (
 	prefixParseFn func() ast.Expression
 	infixParseFn  func(ast.Expression) ast.Expression
@@ -31,7 +85,18 @@ type Parser struct {
 	lex          *lexer.Lexer
 	currentToken token.Token
 	peekToken    token.Token
-	errors       []string
+	errors       []ParseError
+
+	// Set once an error is reported and cleared when the parser has resynchronised, so
+	// that one mistake doesn't produce a cascade of follow-on errors.
+	panicking bool
+
+	// How many blocks are open, so that recovery only stops at a `}` that can close one.
+	blockDepth int
+
+	// How many loops enclose the current token within the current function, so that a
+	// `break` or `continue` with nothing to break out of can be rejected.
+	loopDepth int
 
 	prefixParseFns map[token.TokenType]prefixParseFn
 	infixParseFns  map[token.TokenType]infixParseFn
@@ -43,8 +108,59 @@ func New(lex *lexer.Lexer) *Parser {
 	parser.prefixParseFns = make(map[token.TokenType]prefixParseFn)
 	parser.registerPrefix(token.IDENTIFIER, parser.parseIdentifier)
 	parser.registerPrefix(token.INT, parser.parseIntegerLiteral)
+	parser.registerPrefix(token.FLOAT, parser.parseFloatLiteral)
+	parser.registerPrefix(token.STRING, parser.parseStringLiteral)
+	parser.registerPrefix(token.STRING_START, parser.parseInterpolatedString)
 	parser.registerPrefix(token.BANG, parser.parsePrefixExpression)
 	parser.registerPrefix(token.MINUS, parser.parsePrefixExpression)
+	parser.registerPrefix(token.TILDE, parser.parsePrefixExpression)
+	parser.registerPrefix(token.TRUE, parser.parseBoolean)
+	parser.registerPrefix(token.FALSE, parser.parseBoolean)
+	parser.registerPrefix(token.LPAREN, parser.parseGroupedExpression)
+	parser.registerPrefix(token.LBRACKET, parser.parseArrayLiteral)
+	parser.registerPrefix(token.LBRACE, parser.parseHashLiteral)
+	parser.registerPrefix(token.IF, parser.parseIfExpression)
+	parser.registerPrefix(token.FUNCTION, parser.parseFunctionLiteral)
+
+	parser.infixParseFns = make(map[token.TokenType]infixParseFn)
+	for _, tokenType := range []token.TokenType{
+		token.PLUS,
+		token.MINUS,
+		token.SLASH,
+		token.ASTERISK,
+		token.PERCENT,
+		token.AMPERSAND,
+		token.PIPE,
+		token.CARET,
+		token.SHIFT_LEFT,
+		token.SHIFT_RIGHT,
+		token.EQ,
+		token.NOT_EQ,
+		token.LESS_THAN,
+		token.LESS_THAN_OR_EQ,
+		token.GREATER_THAN,
+		token.GREATER_THAN_OR_EQ,
+	} {
+		parser.registerInfix(tokenType, parser.parseInfixExpression)
+	}
+	for _, tokenType := range []token.TokenType{
+		token.ASSIGN,
+		token.PLUS_ASSIGN,
+		token.MINUS_ASSIGN,
+		token.ASTERISK_ASSIGN,
+		token.SLASH_ASSIGN,
+		token.PERCENT_ASSIGN,
+	} {
+		parser.registerInfix(tokenType, parser.parseAssignExpression)
+	}
+	parser.registerInfix(token.POWER, parser.parsePowerExpression)
+	parser.registerInfix(token.DOTDOT, parser.parseRangeExpression)
+	parser.registerInfix(token.DOTDOT_EQ, parser.parseRangeExpression)
+	parser.registerInfix(token.AND, parser.parseLogicalExpression)
+	parser.registerInfix(token.OR, parser.parseLogicalExpression)
+	parser.registerInfix(token.LPAREN, parser.parseCallExpression)
+	parser.registerInfix(token.LBRACKET, parser.parseIndexExpression)
+
 	// We read two tokens ahead so curToken and peekToken are set by
 	// lexing two tokens. If the input to the lexer is empty, we will
 	// check and see that the curToken is a token.EOF and don't worry about the peekToken in that case.
@@ -57,6 +173,11 @@ func New(lex *lexer.Lexer) *Parser {
 func (p *Parser) NextToken() {
 	p.currentToken = p.peekToken
 	p.peekToken = p.lex.NextToken()
+
+	// Comments have no meaning to the parser, so skip them if the lexer was asked to keep them.
+	for p.peekToken.Type == token.COMMENT {
+		p.peekToken = p.lex.NextToken()
+	}
 }
 
 func (p *Parser) ParseProgram() *ast.Program {
@@ -64,27 +185,89 @@ func (p *Parser) ParseProgram() *ast.Program {
 	program.Statements = []ast.Statement{}
 
 	for p.currentToken.Type != token.EOF {
-		stmt := p.parseStatement()
-		program.Statements = append(program.Statements, stmt)
+		if stmt := p.parseStatementOrRecover(); stmt != nil {
+			program.Statements = append(program.Statements, stmt)
+		}
 		p.NextToken()
 	}
 
+	p.errors = append(p.errors, resolve(program)...)
+	p.addUnreportedLexerErrors()
+
 	return program
 }
 
-func (p *Parser) Errors() []string {
+func (p *Parser) Errors() []ParseError {
 	return p.errors
 }
 
+// parseStatementOrRecover parses a statement, discarding it if any errors were reported
+// along the way. If the parser is still panicking afterwards it skips ahead to the end of
+// the broken statement so that parsing can carry on and report any later errors too.
+func (p *Parser) parseStatementOrRecover() ast.Statement {
+	errorCount := len(p.errors)
+	stmt := p.parseStatement()
+
+	if p.panicking {
+		p.synchronize()
+		p.panicking = false
+	}
+
+	if len(p.errors) > errorCount {
+		return nil
+	}
+	return stmt
+}
+
+// synchronize advances to the last token of the current statement: a `;`, the token before
+// a `}` closing the enclosing block, or the token before the start of the next statement.
+// Outside any block a `}` can't close anything, so it's skipped along with the rest of the
+// broken statement rather than being reported again.
+func (p *Parser) synchronize() {
+	for !p.currentTokenIs(token.SEMICOLON) && !p.currentTokenIs(token.EOF) {
+		switch p.peekToken.Type {
+		case token.RBRACE:
+			if p.blockDepth > 0 {
+				return
+			}
+		case token.EOF, token.LET, token.CONST, token.RETURN, token.WHILE, token.FOR, token.BREAK, token.CONTINUE:
+			return
+		}
+		p.NextToken()
+	}
+}
+
 func (p *Parser) parseStatement() ast.Statement {
+	// The nil checks stop a typed nil pointer from turning into a non-nil ast.Statement.
 	switch p.currentToken.Type {
 	case token.LET:
-		return p.parseLetStatement()
+		if stmt := p.parseLetStatement(); stmt != nil {
+			return stmt
+		}
+	case token.CONST:
+		if stmt := p.parseConstStatement(); stmt != nil {
+			return stmt
+		}
 	case token.RETURN:
-		return p.parseReturnStatement()
+		if stmt := p.parseReturnStatement(); stmt != nil {
+			return stmt
+		}
+	case token.WHILE:
+		if stmt := p.parseWhileStatement(); stmt != nil {
+			return stmt
+		}
+	case token.FOR:
+		if stmt := p.parseForInStatement(); stmt != nil {
+			return stmt
+		}
+	case token.BREAK, token.CONTINUE:
+		return p.parseLoopControlStatement()
 	default:
-		return p.parseExpressionStament()
+		if stmt := p.parseExpressionStament(); stmt != nil {
+			return stmt
+		}
 	}
+	return nil
 }
 
 func (p *Parser) parseLetStatement() *ast.LetStatement {
@@ -100,8 +283,34 @@ func (p *Parser) parseLetStatement() *ast.LetStatement {
 		return nil
 	}
 
-	// TODO: skipping expression parsing for now;
-	for p.currentToken.Type != token.SEMICOLON {
+	p.NextToken()
+	stmt.Value = p.parseExpression(LOWEST)
+
+	// The trailing semicolon is optional, so a let on the last line of input ends at EOF.
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.NextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseConstStatement() *ast.ConstStatement {
+	stmt := &ast.ConstStatement{Token: p.currentToken}
+
+	if !p.expectPeek(token.IDENTIFIER) {
+		return nil
+	}
+
+	stmt.Name = &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+
+	if !p.expectPeek(token.ASSIGN) {
+		return nil
+	}
+
+	p.NextToken()
+	stmt.Value = p.parseExpression(LOWEST)
+
+	if p.peekTokenIs(token.SEMICOLON) {
 		p.NextToken()
 	}
 
@@ -112,19 +321,123 @@ func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 	stmt := &ast.ReturnStatement{Token: p.currentToken}
 
 	p.NextToken()
+	stmt.Value = p.parseExpression(LOWEST)
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.NextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseWhileStatement() *ast.WhileStatement {
+	stmt := &ast.WhileStatement{Token: p.currentToken}
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+
+	p.NextToken()
+	stmt.Condition = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	p.loopDepth++
+	stmt.Body = p.parseBlockStatement()
+	p.loopDepth--
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.NextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseForInStatement() *ast.ForInStatement {
+	stmt := &ast.ForInStatement{Token: p.currentToken}
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+
+	if !p.expectPeek(token.IDENTIFIER) {
+		return nil
+	}
+	stmt.Value = &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+
+	// With two loop variables, the first takes the key or index and the second the value.
+	if p.peekTokenIs(token.COMMA) {
+		p.NextToken()
+		if !p.expectPeek(token.IDENTIFIER) {
+			return nil
+		}
+		stmt.Key = stmt.Value
+		stmt.Value = &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+	}
+
+	if !p.expectPeek(token.IN) {
+		return nil
+	}
+
+	p.NextToken()
+	stmt.Iterable = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
 
-	// TODO: skipping expression parsing for now;
-	for p.currentToken.Type != token.SEMICOLON {
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	p.loopDepth++
+	stmt.Body = p.parseBlockStatement()
+	p.loopDepth--
+
+	if p.peekTokenIs(token.SEMICOLON) {
 		p.NextToken()
 	}
 
 	return stmt
 }
 
+// parseLoopControlStatement parses a `break` or `continue`, reporting an error at it if
+// it isn't inside a loop.
+func (p *Parser) parseLoopControlStatement() ast.Statement {
+	tok := p.currentToken
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.NextToken()
+	}
+
+	if p.loopDepth == 0 {
+		p.addError(ParseError{
+			Pos:     tok.Pos,
+			Found:   tok,
+			Message: fmt.Sprintf("%s is not inside a loop.", tok.Literal),
+		})
+		return nil
+	}
+
+	if tok.Type == token.BREAK {
+		return &ast.BreakStatement{Token: tok}
+	}
+	return &ast.ContinueStatement{Token: tok}
+}
+
 func (p *Parser) parseExpressionStament() *ast.ExpressionStatement {
 	stmt := &ast.ExpressionStatement{Token: p.currentToken}
 
 	stmt.Expression = p.parseExpression(LOWEST)
+	if stmt.Expression == nil {
+		return nil
+	}
 
 	if p.peekTokenIs(token.SEMICOLON) {
 		p.NextToken()
@@ -132,9 +445,61 @@ func (p *Parser) parseExpressionStament() *ast.ExpressionStatement {
 	return stmt
 }
 
+// addError records err unless we're already recovering from an earlier one. Tripping over
+// an ILLEGAL token is reported using the lexer's explanation of what was wrong with it,
+// rather than as a token the parser didn't expect.
+func (p *Parser) addError(err ParseError) {
+	if p.panicking {
+		return
+	}
+
+	if err.Found.Type == token.ILLEGAL {
+		if lexErr, ok := p.lexerErrorWithin(err.Found); ok {
+			err.Pos = lexErr.Pos
+			err.Expected = token.UNKNOWN
+			err.Message = lexErr.Message
+		}
+	}
+
+	p.errors = append(p.errors, err)
+	p.panicking = true
+}
+
+func (p *Parser) lexerErrorWithin(tok token.Token) (lexer.Error, bool) {
+	for _, lexErr := range p.lex.Errors() {
+		if tok.Pos.Offset <= lexErr.Pos.Offset && lexErr.Pos.Offset < max(tok.End.Offset, tok.Pos.Offset+1) {
+			return lexErr, true
+		}
+	}
+	return lexer.Error{}, false
+}
+
+// addUnreportedLexerErrors adds any lexer errors the parser skipped past while recovering,
+// or that shared an ILLEGAL token with one it did report, keeping the errors in source order.
+func (p *Parser) addUnreportedLexerErrors() {
+	reported := make(map[int]bool)
+	for _, err := range p.errors {
+		reported[err.Pos.Offset] = true
+	}
+
+	for _, lexErr := range p.lex.Errors() {
+		if reported[lexErr.Pos.Offset] {
+			continue
+		}
+		p.errors = append(p.errors, ParseError{Pos: lexErr.Pos, Message: lexErr.Message})
+	}
+
+	sort.SliceStable(p.errors, func(i, j int) bool {
+		return p.errors[i].Pos.Offset < p.errors[j].Pos.Offset
+	})
+}
+
 func (p *Parser) noPrefixParseFnError(t token.TokenType) {
-	msg := fmt.Sprintf("no prefix parse function for %s found", t)
-	p.errors = append(p.errors, msg)
+	p.addError(ParseError{
+		Pos:     p.currentToken.Pos,
+		Found:   p.currentToken,
+		Message: fmt.Sprintf("no prefix parse function for %s found", t),
+	})
 }
 
 func (p *Parser) parseExpression(precedence OperatorPrecedence) ast.Expression {
@@ -144,6 +509,25 @@ func (p *Parser) parseExpression(precedence OperatorPrecedence) ast.Expression {
 		return nil
 	}
 	leftExp := prefix()
+	if leftExp == nil {
+		return nil
+	}
+
+	// Keep folding the expression built so far into the left operand of the next infix
+	// operator for as long as that operator binds tighter than the one we were called for.
+	for !p.peekTokenIs(token.SEMICOLON) && precedence < p.peekPrecedence() {
+		infix := p.infixParseFns[p.peekToken.Type]
+		if infix == nil {
+			return leftExp
+		}
+
+		p.NextToken()
+		leftExp = infix(leftExp)
+		if leftExp == nil {
+			return nil
+		}
+	}
+
 	return leftExp
 }
 
@@ -154,10 +538,13 @@ func (p *Parser) parseIdentifier() ast.Expression {
 func (p *Parser) parseIntegerLiteral() ast.Expression {
 	intLit := &ast.IntegerLiteral{Token: p.currentToken}
 
-	value, err := strconv.ParseInt(p.currentToken.Literal, 0, 64)
+	value, err := parseInteger(p.currentToken.Literal)
 	if err != nil {
-		msg := fmt.Sprintf("could not parse %q as integer", p.currentToken.Literal)
-		p.errors = append(p.errors, msg)
+		p.addError(ParseError{
+			Pos:     p.currentToken.Pos,
+			Found:   p.currentToken,
+			Message: fmt.Sprintf("could not parse %q as integer", p.currentToken.Literal),
+		})
 		return nil
 	}
 
@@ -165,6 +552,96 @@ func (p *Parser) parseIntegerLiteral() ast.Expression {
 	return intLit
 }
 
+// parseInteger converts an INT literal, which the lexer has already checked is well formed,
+// to its value. Unlike strconv's base 0, a leading 0 doesn't make a literal octal, so 017 is 17.
+func parseInteger(literal string) (int64, error) {
+	digits := strings.ReplaceAll(literal, "_", "")
+
+	base := 10
+	if len(digits) > 2 && digits[0] == '0' {
+		switch digits[1] {
+		case 'x', 'X':
+			base = 16
+		case 'o', 'O':
+			base = 8
+		case 'b', 'B':
+			base = 2
+		}
+	}
+	if base != 10 {
+		digits = digits[2:]
+	}
+
+	return strconv.ParseInt(digits, base, 64)
+}
+
+func (p *Parser) parseFloatLiteral() ast.Expression {
+	floatLit := &ast.FloatLiteral{Token: p.currentToken}
+
+	value, err := strconv.ParseFloat(strings.ReplaceAll(p.currentToken.Literal, "_", ""), 64)
+	if err != nil {
+		p.addError(ParseError{
+			Pos:     p.currentToken.Pos,
+			Found:   p.currentToken,
+			Message: fmt.Sprintf("could not parse %q as float", p.currentToken.Literal),
+		})
+		return nil
+	}
+
+	floatLit.Value = value
+	return floatLit
+}
+
+func (p *Parser) parseStringLiteral() ast.Expression {
+	return &ast.StringLiteral{Token: p.currentToken, Value: p.currentToken.Literal}
+}
+
+func (p *Parser) parseInterpolatedString() ast.Expression {
+	str := &ast.InterpolatedString{Token: p.currentToken}
+	str.Segments = []ast.Expression{p.parseStringLiteral()}
+
+	for {
+		p.NextToken()
+		expression := p.parseExpression(LOWEST)
+		if expression == nil {
+			return nil
+		}
+		str.Segments = append(str.Segments, expression)
+
+		switch p.peekToken.Type {
+		case token.STRING_MID:
+			p.NextToken()
+			str.Segments = append(str.Segments, p.parseStringLiteral())
+		case token.STRING_END:
+			p.NextToken()
+			str.Segments = append(str.Segments, p.parseStringLiteral())
+			str.EndToken = p.currentToken
+			return str
+		default:
+			p.peekError(token.STRING_END)
+			return nil
+		}
+	}
+}
+
+func (p *Parser) parseBoolean() ast.Expression {
+	return &ast.Boolean{Token: p.currentToken, Value: p.currentTokenIs(token.TRUE)}
+}
+
+// Grouping needs no node of its own: parsing the inner expression at LOWEST is enough to
+// make it bind as a single operand.
+func (p *Parser) parseGroupedExpression() ast.Expression {
+	p.NextToken()
+
+	expression := p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+
+	return expression
+}
+
 func (p *Parser) parsePrefixExpression() ast.Expression {
 	expression := &ast.PrefixExpression{Token: p.currentToken, Operator: p.currentToken.Literal}
 	// We're currently looking at the operator so we shift once to the operand
@@ -174,9 +651,378 @@ func (p *Parser) parsePrefixExpression() ast.Expression {
 	return expression
 }
 
+func (p *Parser) parseIfExpression() ast.Expression {
+	expression := &ast.IfExpression{Token: p.currentToken}
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+
+	p.NextToken()
+	expression.Condition = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	expression.Consequence = p.parseBlockStatement()
+
+	if !p.peekTokenIs(token.ELSE) {
+		return expression
+	}
+	p.NextToken()
+
+	// `else if` is sugar for an else block whose only statement is the nested if.
+	if p.peekTokenIs(token.IF) {
+		p.NextToken()
+		elseTok := p.currentToken
+		nested := p.parseIfExpression()
+		if nested == nil {
+			return nil
+		}
+		expression.Alternative = &ast.BlockStatement{
+			Token:      elseTok,
+			Statements: []ast.Statement{&ast.ExpressionStatement{Token: elseTok, Expression: nested}},
+		}
+		return expression
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	expression.Alternative = p.parseBlockStatement()
+
+	return expression
+}
+
+func (p *Parser) parseBlockStatement() *ast.BlockStatement {
+	block := &ast.BlockStatement{Token: p.currentToken}
+	block.Statements = []ast.Statement{}
+
+	p.NextToken()
+
+	p.blockDepth++
+	for !p.currentTokenIs(token.RBRACE) && !p.currentTokenIs(token.EOF) {
+		if stmt := p.parseStatementOrRecover(); stmt != nil {
+			block.Statements = append(block.Statements, stmt)
+		}
+		p.NextToken()
+	}
+	p.blockDepth--
+
+	if !p.currentTokenIs(token.RBRACE) {
+		p.addError(ParseError{
+			Pos:      p.currentToken.Pos,
+			Expected: token.RBRACE,
+			Found:    p.currentToken,
+			Message:  "expected RBRACE to close block, got EOF.",
+		})
+		return block
+	}
+	block.Rbrace = p.currentToken
+
+	return block
+}
+
+func (p *Parser) parseFunctionLiteral() ast.Expression {
+	literal := &ast.FunctionLiteral{Token: p.currentToken}
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+
+	literal.Parameters = p.parseFunctionParameters()
+	if literal.Parameters == nil {
+		return nil
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	// A loop around the function literal can't be broken out of from inside its body.
+	loopDepth := p.loopDepth
+	p.loopDepth = 0
+	literal.Body = p.parseBlockStatement()
+	p.loopDepth = loopDepth
+
+	return literal
+}
+
+func (p *Parser) parseFunctionParameters() []*ast.Identifier {
+	identifiers := []*ast.Identifier{}
+
+	if p.peekTokenIs(token.RPAREN) {
+		p.NextToken()
+		return identifiers
+	}
+
+	if !p.expectPeek(token.IDENTIFIER) {
+		return nil
+	}
+	identifiers = append(identifiers, &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal})
+
+	for p.peekTokenIs(token.COMMA) {
+		p.NextToken()
+		if !p.expectPeek(token.IDENTIFIER) {
+			return nil
+		}
+		identifiers = append(identifiers, &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal})
+	}
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+
+	return identifiers
+}
+
+// Calls are parsed as an infix operator on '(' so that the callee can be any expression,
+// e.g. an identifier, a function literal, or the result of another call.
+func (p *Parser) parseCallExpression(function ast.Expression) ast.Expression {
+	expression := &ast.CallExpression{Token: p.currentToken, Function: function}
+	expression.Arguments = p.parseExpressionList(token.RPAREN)
+	if expression.Arguments == nil {
+		return nil
+	}
+	expression.Rparen = p.currentToken
+	return expression
+}
+
+// parseExpressionList parses comma-separated expressions up to the end token, allowing a
+// trailing comma after the last one. It returns nil if the list was malformed.
+func (p *Parser) parseExpressionList(end token.TokenType) []ast.Expression {
+	list := []ast.Expression{}
+
+	for !p.peekTokenIs(end) {
+		p.NextToken()
+		expression := p.parseExpression(LOWEST)
+		if expression == nil {
+			return nil
+		}
+		list = append(list, expression)
+
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+		p.NextToken()
+	}
+
+	if !p.expectPeek(end) {
+		return nil
+	}
+
+	return list
+}
+
+func (p *Parser) parseArrayLiteral() ast.Expression {
+	array := &ast.ArrayLiteral{Token: p.currentToken}
+
+	array.Elements = p.parseExpressionList(token.RBRACKET)
+	if array.Elements == nil {
+		return nil
+	}
+	array.Rbracket = p.currentToken
+
+	return array
+}
+
+// A '{' is only parsed as a block where a statement's syntax calls for one, such as after
+// `if`, `else` or `fn(...)`, so anywhere an expression can start it begins a hash literal
+// instead. That includes the start of a statement: there are no bare blocks, and `{ x }`
+// on its own line is a malformed hash literal, while `{}` is an empty hash.
+func (p *Parser) parseHashLiteral() ast.Expression {
+	hash := &ast.HashLiteral{Token: p.currentToken}
+
+	for !p.peekTokenIs(token.RBRACE) {
+		p.NextToken()
+		key := p.parseExpression(LOWEST)
+		if key == nil {
+			return nil
+		}
+
+		if !p.expectPeek(token.COLON) {
+			return nil
+		}
+
+		p.NextToken()
+		value := p.parseExpression(LOWEST)
+		if value == nil {
+			return nil
+		}
+
+		hash.Pairs = append(hash.Pairs, ast.HashPair{Key: key, Value: value})
+
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+		p.NextToken()
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+	hash.Rbrace = p.currentToken
+
+	return hash
+}
+
+// parseIndexExpression parses both `left[index]` and the slice form `left[low:high]`.
+func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
+	bracket := p.currentToken
+	p.NextToken()
+
+	var low ast.Expression
+	if !p.currentTokenIs(token.COLON) {
+		low = p.parseExpression(LOWEST)
+		if low == nil {
+			return nil
+		}
+
+		if !p.peekTokenIs(token.COLON) {
+			if !p.expectPeek(token.RBRACKET) {
+				return nil
+			}
+			return &ast.IndexExpression{Token: bracket, Left: left, Index: low, Rbracket: p.currentToken}
+		}
+		p.NextToken()
+	}
+
+	slice := &ast.SliceExpression{Token: bracket, Left: left, Low: low}
+
+	if !p.peekTokenIs(token.RBRACKET) {
+		p.NextToken()
+		slice.High = p.parseExpression(LOWEST)
+		if slice.High == nil {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACKET) {
+		return nil
+	}
+	slice.Rbracket = p.currentToken
+
+	return slice
+}
+
+func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
+	expression := &ast.InfixExpression{
+		Token:    p.currentToken,
+		Operator: p.currentToken.Literal,
+		Left:     left,
+	}
+
+	precedence := p.currentPrecedence()
+	p.NextToken()
+	expression.Right = p.parseExpression(precedence)
+
+	return expression
+}
+
+// parseAssignExpression parses the value at a lower precedence than ASSIGN so that assignment
+// is right-associative: `a = b = 1` assigns 1 to b and then to a.
+func (p *Parser) parseAssignExpression(target ast.Expression) ast.Expression {
+	expression := &ast.AssignExpression{
+		Token:    p.currentToken,
+		Target:   target,
+		Operator: p.currentToken.Literal,
+	}
+
+	switch target.(type) {
+	case *ast.Identifier, *ast.IndexExpression:
+	default:
+		p.addError(ParseError{
+			Pos:     target.Pos(),
+			Found:   p.currentToken,
+			Message: fmt.Sprintf("cannot assign to %s.", target.String()),
+		})
+		return nil
+	}
+
+	p.NextToken()
+	expression.Value = p.parseExpression(ASSIGN - 1)
+	if expression.Value == nil {
+		return nil
+	}
+
+	return expression
+}
+
+// parsePowerExpression parses the right operand one level looser than POWER, so another
+// `**` there is taken into it and the operator is right-associative: `2 ** 3 ** 2` is
+// `2 ** (3 ** 2)`.
+func (p *Parser) parsePowerExpression(left ast.Expression) ast.Expression {
+	expression := &ast.InfixExpression{
+		Token:    p.currentToken,
+		Operator: p.currentToken.Literal,
+		Left:     left,
+	}
+
+	p.NextToken()
+	expression.Right = p.parseExpression(POWER - 1)
+
+	return expression
+}
+
+func (p *Parser) parseRangeExpression(low ast.Expression) ast.Expression {
+	expression := &ast.RangeExpression{
+		Token:     p.currentToken,
+		Low:       low,
+		Inclusive: p.currentTokenIs(token.DOTDOT_EQ),
+	}
+
+	precedence := p.currentPrecedence()
+	p.NextToken()
+	expression.High = p.parseExpression(precedence)
+
+	return expression
+}
+
+func (p *Parser) parseLogicalExpression(left ast.Expression) ast.Expression {
+	expression := &ast.LogicalExpression{
+		Token:    p.currentToken,
+		Operator: p.currentToken.Literal,
+		Left:     left,
+	}
+
+	precedence := p.currentPrecedence()
+	p.NextToken()
+	expression.Right = p.parseExpression(precedence)
+
+	return expression
+}
+
+func (p *Parser) peekPrecedence() OperatorPrecedence {
+	if precedence, ok := precedences[p.peekToken.Type]; ok {
+		return precedence
+	}
+	return LOWEST
+}
+
+func (p *Parser) currentPrecedence() OperatorPrecedence {
+	if precedence, ok := precedences[p.currentToken.Type]; ok {
+		return precedence
+	}
+	return LOWEST
+}
+
 func (p *Parser) peekError(expectedType token.TokenType) {
-	msg := fmt.Sprintf("expected next token to be %s, got %s.", expectedType, p.peekToken.Type)
-	p.errors = append(p.errors, msg)
+	p.addError(ParseError{
+		Pos:      p.peekToken.Pos,
+		Expected: expectedType,
+		Found:    p.peekToken,
+		Message:  fmt.Sprintf("expected next token to be %s, got %s.", expectedType, p.peekToken.Type),
+	})
+}
+
+func (p *Parser) currentTokenIs(expectedType token.TokenType) bool {
+	return p.currentToken.Type == expectedType
 }
 
 func (p *Parser) peekTokenIs(expectedType token.TokenType) bool {
@@ -6,6 +6,7 @@ import (
 
 	"github.com/MichaelBo1/go_interpreter/ast"
 	"github.com/MichaelBo1/go_interpreter/lexer"
+	"github.com/MichaelBo1/go_interpreter/token"
 )
 
 func TestParsesLetStatement(t *testing.T) {
@@ -73,6 +74,97 @@ func TestParsesReturnStatments(t *testing.T) {
 	}
 }
 
+func TestLetStatementValues(t *testing.T) {
+	tests := []struct {
+		input              string
+		expectedIdentifier string
+		expectedValue      string
+	}{
+		{"let x = 5;", "x", "5"},
+		{"let y = 10", "y", "10"},
+		{"let foobar = y * 2 + 1;", "foobar", "((y * 2) + 1)"},
+	}
+
+	for _, test := range tests {
+		lex := lexer.New(test.input)
+		par := New(lex)
+
+		program := par.ParseProgram()
+		if program == nil {
+			t.Fatalf("ParseProgram() returned nil")
+		}
+		checkParserErrors(t, par)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program.Statements does not contain 1 statement. Got %d statements", len(program.Statements))
+		}
+
+		stmt := program.Statements[0]
+		testLetStatement(t, stmt, test.expectedIdentifier)
+
+		value := stmt.(*ast.LetStatement).Value
+		if value == nil || value.String() != test.expectedValue {
+			t.Errorf("letStmt.Value not %q. Got %v", test.expectedValue, value)
+		}
+	}
+}
+
+func TestReturnStatementValues(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedValue string
+	}{
+		{"return 5;", "5"},
+		{"return 1337", "1337"},
+		{"return a + b;", "(a + b)"},
+	}
+
+	for _, test := range tests {
+		lex := lexer.New(test.input)
+		par := New(lex)
+
+		program := par.ParseProgram()
+		if program == nil {
+			t.Fatalf("ParseProgram() returned nil")
+		}
+		checkParserErrors(t, par)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program.Statements does not contain 1 statement. Got %d statements", len(program.Statements))
+		}
+
+		returnStmt, ok := program.Statements[0].(*ast.ReturnStatement)
+		if !ok {
+			t.Fatalf("stmt is not an *ast.ReturnStatement")
+		}
+
+		if returnStmt.TokenLiteral() != "return" {
+			t.Errorf("returnStmt.TokenLiteral not 'return', instead got %q", returnStmt.TokenLiteral())
+		}
+
+		if returnStmt.Value == nil || returnStmt.Value.String() != test.expectedValue {
+			t.Errorf("returnStmt.Value not %q. Got %v", test.expectedValue, returnStmt.Value)
+		}
+	}
+}
+
+func TestProgramStringRoundTrip(t *testing.T) {
+	input := `
+	let x = 5;
+	let y = x + 10
+	return y;`
+
+	lex := lexer.New(input)
+	par := New(lex)
+	program := par.ParseProgram()
+	checkParserErrors(t, par)
+
+	expected := "let x = 5;let y = (x + 10);return y;"
+	if program.String() != expected {
+		t.Errorf("program.String() wrong. expected=%q, got=%q", expected, program.String())
+	}
+}
+
 func TestParsesIdentifierExpression(t *testing.T) {
 	input := "foobar;"
 
@@ -144,6 +236,67 @@ func TestIntegerLiteralExpression(t *testing.T) {
 	}
 }
 
+func TestPrefixedIntegerLiterals(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"0xFF", 255},
+		{"0Xff", 255},
+		{"0o17", 15},
+		{"0b1010", 10},
+		{"1_000_000", 1000000},
+		{"0x_7FFF_FFFF_FFFF_FFFF", 9223372036854775807},
+		{"017", 17},
+	}
+
+	for _, test := range tests {
+		lex := lexer.New(test.input)
+		par := New(lex)
+		program := par.ParseProgram()
+		checkParserErrors(t, par)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		literal, ok := stmt.Expression.(*ast.IntegerLiteral)
+		if !ok {
+			t.Fatalf("exp not *ast.IntegerLiteral. got=%T", stmt.Expression)
+		}
+
+		if literal.Value != test.expected {
+			t.Errorf("literal.Value for %s not %d. got=%d", test.input, test.expected, literal.Value)
+		}
+	}
+}
+
+func TestFloatLiteralExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+	}{
+		{"3.14;", 3.14},
+		{"1e-9", 1e-9},
+		{"2.5E3", 2500},
+		{"1_000.25", 1000.25},
+	}
+
+	for _, test := range tests {
+		lex := lexer.New(test.input)
+		par := New(lex)
+		program := par.ParseProgram()
+		checkParserErrors(t, par)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		literal, ok := stmt.Expression.(*ast.FloatLiteral)
+		if !ok {
+			t.Fatalf("exp not *ast.FloatLiteral. got=%T", stmt.Expression)
+		}
+
+		if literal.Value != test.expected {
+			t.Errorf("literal.Value not %g. got=%g", test.expected, literal.Value)
+		}
+	}
+}
+
 func TestParsingPrefixExpressions(t *testing.T) {
 	prefixTests := []struct {
 		input            string
@@ -190,6 +343,1277 @@ func TestParsingPrefixExpressions(t *testing.T) {
 	}
 }
 
+func TestParsingInfixExpressions(t *testing.T) {
+	infixTests := []struct {
+		input      string
+		leftValue  int64
+		operator   string
+		rightValue int64
+	}{
+		{"5 + 5;", 5, "+", 5},
+		{"5 - 5;", 5, "-", 5},
+		{"5 * 5;", 5, "*", 5},
+		{"5 / 5;", 5, "/", 5},
+		{"5 > 5;", 5, ">", 5},
+		{"5 < 5;", 5, "<", 5},
+		{"5 >= 5;", 5, ">=", 5},
+		{"5 <= 5;", 5, "<=", 5},
+		{"5 == 5;", 5, "==", 5},
+		{"5 != 5;", 5, "!=", 5},
+		{"5 % 5;", 5, "%", 5},
+		{"5 ** 5;", 5, "**", 5},
+		{"5 & 5;", 5, "&", 5},
+		{"5 | 5;", 5, "|", 5},
+		{"5 ^ 5;", 5, "^", 5},
+		{"5 << 5;", 5, "<<", 5},
+		{"5 >> 5;", 5, ">>", 5},
+	}
+
+	for _, test := range infixTests {
+		lex := lexer.New(test.input)
+		par := New(lex)
+		program := par.ParseProgram()
+		checkParserErrors(t, par)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program.Statements does not contain %d statements. got=%d\n",
+				1, len(program.Statements))
+		}
+
+		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T",
+				program.Statements[0])
+		}
+
+		exp, ok := stmt.Expression.(*ast.InfixExpression)
+		if !ok {
+			t.Fatalf("exp is not ast.InfixExpression. got=%T", stmt.Expression)
+		}
+
+		if !testIntegerLiteral(t, exp.Left, test.leftValue) {
+			return
+		}
+
+		if exp.Operator != test.operator {
+			t.Fatalf("exp.Operator is not '%s'. got=%s", test.operator, exp.Operator)
+		}
+
+		if !testIntegerLiteral(t, exp.Right, test.rightValue) {
+			return
+		}
+	}
+}
+
+func TestOperatorPrecedenceParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"-a * b", "((-a) * b)"},
+		{"!-a", "(!(-a))"},
+		{"a + b + c", "((a + b) + c)"},
+		{"a + b - c", "((a + b) - c)"},
+		{"a * b * c", "((a * b) * c)"},
+		{"a * b / c", "((a * b) / c)"},
+		{"a + b / c", "(a + (b / c))"},
+		{"a + b * c + d / e - f", "(((a + (b * c)) + (d / e)) - f)"},
+		{"3 + 4; -5 * 5", "(3 + 4)((-5) * 5)"},
+		{"5 > 4 == 3 < 4", "((5 > 4) == (3 < 4))"},
+		{"5 <= 4 != 3 >= 4", "((5 <= 4) != (3 >= 4))"},
+		{"3 + 4 * 5 == 3 * 1 + 4 * 5", "((3 + (4 * 5)) == ((3 * 1) + (4 * 5)))"},
+		{"true", "true"},
+		{"false", "false"},
+		{"3 > 5 == false", "((3 > 5) == false)"},
+		{"3 < 5 == true", "((3 < 5) == true)"},
+		{"!true", "(!true)"},
+		{"!!false", "(!(!false))"},
+		{"1 + (2 + 3) + 4", "((1 + (2 + 3)) + 4)"},
+		{"(5 + 5) * 2", "((5 + 5) * 2)"},
+		{"(1 + 2) * 3", "((1 + 2) * 3)"},
+		{"2 / (5 + 5)", "(2 / (5 + 5))"},
+		{"-(5 + 5)", "(-(5 + 5))"},
+		{"!(true == true)", "(!(true == true))"},
+		{"((a))", "a"},
+		{"a || b && c", "(a || (b && c))"},
+		{"a && b || c", "((a && b) || c)"},
+		{"a || b || c", "((a || b) || c)"},
+		{"a == b && c != d", "((a == b) && (c != d))"},
+		{"a < b || !c", "((a < b) || (!c))"},
+		{"(a || b) && c", "((a || b) && c)"},
+		{"a * b % c", "((a * b) % c)"},
+		{"a + b % c", "(a + (b % c))"},
+		{"a ** b ** c", "(a ** (b ** c))"},
+		{"a * b ** c", "(a * (b ** c))"},
+		{"-a ** b", "(-(a ** b))"},
+		{"a ** -b", "(a ** (-b))"},
+		{"a ** -b * c", "((a ** (-b)) * c)"},
+		{"!a ** b", "(!(a ** b))"},
+		{"a ** b[0]", "(a ** (b[0]))"},
+		{"~a & b", "((~a) & b)"},
+		{"a | b ^ c & d", "(a | (b ^ (c & d)))"},
+		{"a & b == 0", "((a & b) == 0)"},
+		{"a | b < c", "((a | b) < c)"},
+		{"1 << n - 1", "(1 << (n - 1))"},
+		{"a << b & c", "((a << b) & c)"},
+		{"a >> b >> c", "((a >> b) >> c)"},
+		{"a & b && c | d", "((a & b) && (c | d))"},
+		{"a = b = c", "(a = (b = c))"},
+		{"x += 1 * 2", "(x += (1 * 2))"},
+		{"x = y || z", "(x = (y || z))"},
+		{"x = a..b", "(x = (a..b))"},
+		{"a[i + 1] = b[i]", "((a[(i + 1)]) = (b[i]))"},
+		{"h[\"k\"] %= 2", "((h[\"k\"]) %= 2)"},
+		{"f(x = 1)", "f((x = 1))"},
+		{"0..n - 1", "(0..(n - 1))"},
+		{"a..=b * 2", "(a..=(b * 2))"},
+		{"i < 0..n", "(i < (0..n))"},
+		{"a..b == c", "((a..b) == c)"},
+		{"x & 1..y | 2", "((x & 1)..(y | 2))"},
+		{"-a..b[0]", "((-a)..(b[0]))"},
+	}
+
+	for _, test := range tests {
+		lex := lexer.New(test.input)
+		par := New(lex)
+		program := par.ParseProgram()
+		checkParserErrors(t, par)
+
+		actual := program.String()
+		if actual != test.expected {
+			t.Errorf("expected=%q, got=%q", test.expected, actual)
+		}
+	}
+}
+
+func TestConstStatements(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedName  string
+		expectedValue string
+	}{
+		{"const x = 5;", "x", "5"},
+		{"const LIMIT = 10 * 2", "LIMIT", "(10 * 2)"},
+		{`const config = {"debug": false};`, "config", `{"debug": false}`},
+	}
+
+	for _, test := range tests {
+		lex := lexer.New(test.input)
+		par := New(lex)
+		program := par.ParseProgram()
+		checkParserErrors(t, par)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program.Statements does not contain 1 statement. got=%d", len(program.Statements))
+		}
+
+		stmt, ok := program.Statements[0].(*ast.ConstStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not ast.ConstStatement. got=%T", program.Statements[0])
+		}
+
+		if stmt.Name.Value != test.expectedName {
+			t.Errorf("stmt.Name.Value wrong. expected=%q, got=%q", test.expectedName, stmt.Name.Value)
+		}
+
+		if stmt.Value.String() != test.expectedValue {
+			t.Errorf("stmt.Value wrong. expected=%q, got=%q", test.expectedValue, stmt.Value.String())
+		}
+	}
+}
+
+func TestMalformedConstStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"const = 5;", "1:7: expected next token to be IDENTIFIER, got ASSIGN."},
+		{"const x;", "1:8: expected next token to be ASSIGN, got SEMICOLON."},
+		{"const x 5", "1:9: expected next token to be ASSIGN, got INT."},
+	}
+
+	for _, test := range tests {
+		lex := lexer.New(test.input)
+		par := New(lex)
+		par.ParseProgram()
+
+		errors := par.Errors()
+		if len(errors) != 1 || errors[0].Error() != test.expected {
+			t.Errorf("wrong errors for %q. expected=%q, got=%v", test.input, test.expected, errors)
+		}
+	}
+}
+
+func TestResolverRejectsMisusedConstants(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"const x = 1; x = 2;", []string{"1:14: cannot assign to constant x."}},
+		{"const x = 1; x += 2;", []string{"1:14: cannot assign to constant x."}},
+		{"const x = 1; const x = 2;", []string{"1:20: cannot redeclare constant x."}},
+		{"const x = 1; let x = 2;", []string{"1:18: cannot redeclare constant x."}},
+		{"let x = 1; const x = 2;", []string{"1:18: x is already declared in this scope."}},
+		{"const x = 1;\nif (true) { x = 2 }", []string{"2:13: cannot assign to constant x."}},
+		{"const x = 1; let f = fn() { x = 2 };", []string{"1:29: cannot assign to constant x."}},
+		{"const x = 1; while (true) { x -= 1; break }", []string{"1:29: cannot assign to constant x."}},
+		{"fn(a) { const a = 1 }", []string{"1:15: a is already declared in this scope."}},
+		{"for (i in xs) { const i = 0 }", []string{"1:23: i is already declared in this scope."}},
+		{"const a = 1; const b = 2; a = b = 3;", []string{
+			"1:27: cannot assign to constant a.",
+			"1:31: cannot assign to constant b.",
+		}},
+		{"let y = ; const x = 1; x = 2", []string{
+			"1:9: no prefix parse function for SEMICOLON found",
+			"1:24: cannot assign to constant x.",
+		}},
+	}
+
+	for _, test := range tests {
+		lex := lexer.New(test.input)
+		par := New(lex)
+		par.ParseProgram()
+
+		errors := par.Errors()
+		if len(errors) != len(test.expected) {
+			t.Errorf("wrong number of errors for %q. expected=%d, got=%v", test.input, len(test.expected), errors)
+			continue
+		}
+
+		for i, err := range errors {
+			if err.Error() != test.expected[i] {
+				t.Errorf("errors[%d] wrong for %q. expected=%q, got=%q", i, test.input, test.expected[i], err.Error())
+			}
+		}
+	}
+}
+
+func TestResolverAllowsShadowingConstants(t *testing.T) {
+	inputs := []string{
+		"let x = 1; let x = 2; x = 3;",
+		"const x = 1; if (true) { let x = 2; x = 3 }",
+		"const x = 1; if (true) { const x = 2 }",
+		"const x = 1; let f = fn(x) { x = 2 };",
+		"const x = 1; for (x in xs) { x = 2 }",
+		"const a = [1]; a[0] = 2;",
+		`const h = {}; h["k"] = 1;`,
+		"x = 1; const y = 2;",
+		"const x = 1; let f = fn() { let x = 0; x += 1 };",
+	}
+
+	for _, input := range inputs {
+		lex := lexer.New(input)
+		par := New(lex)
+		par.ParseProgram()
+		checkParserErrors(t, par)
+	}
+}
+
+func TestAssignExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		target   string
+		operator string
+		value    string
+	}{
+		{"x = 5;", "x", "=", "5"},
+		{"x += y", "x", "+=", "y"},
+		{"x -= 1", "x", "-=", "1"},
+		{"x *= 2", "x", "*=", "2"},
+		{"x /= 2", "x", "/=", "2"},
+		{"x %= 2", "x", "%=", "2"},
+		{"a[0] = 1", "(a[0])", "=", "1"},
+		{`h["k"] = v`, `(h["k"])`, "=", "v"},
+		{"a[0][1] += 1", "((a[0])[1])", "+=", "1"},
+		{"(x) = 1", "x", "=", "1"},
+	}
+
+	for _, test := range tests {
+		lex := lexer.New(test.input)
+		par := New(lex)
+		program := par.ParseProgram()
+		checkParserErrors(t, par)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		exp, ok := stmt.Expression.(*ast.AssignExpression)
+		if !ok {
+			t.Fatalf("exp is not ast.AssignExpression. got=%T", stmt.Expression)
+		}
+
+		if exp.Target.String() != test.target || exp.Operator != test.operator || exp.Value.String() != test.value {
+			t.Errorf("wrong expression for %q. got=%q %q %q",
+				test.input, exp.Target.String(), exp.Operator, exp.Value.String())
+		}
+	}
+}
+
+func TestInvalidAssignmentTargets(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1 = 2", "1:1: cannot assign to 1."},
+		{"f() = 3", "1:1: cannot assign to f()."},
+		{"let x = 1;\n  a + b = c", "2:3: cannot assign to (a + b)."},
+		{"-x = 1", "1:1: cannot assign to (-x)."},
+		{"a[1:2] = [3]", "1:1: cannot assign to (a[1:2])."},
+		{`"s" += "t"`, `1:1: cannot assign to "s".`},
+		{"x = ", "1:5: no prefix parse function for EOF found"},
+	}
+
+	for _, test := range tests {
+		lex := lexer.New(test.input)
+		par := New(lex)
+		par.ParseProgram()
+
+		errors := par.Errors()
+		if len(errors) != 1 || errors[0].Error() != test.expected {
+			t.Errorf("wrong errors for %q. expected=%q, got=%v", test.input, test.expected, errors)
+		}
+	}
+}
+
+func TestLogicalExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		left     string
+		operator string
+		right    string
+	}{
+		{"x && y", "x", "&&", "y"},
+		{"x || y", "x", "||", "y"},
+		{"a == 1 || f(b)", "(a == 1)", "||", "f(b)"},
+	}
+
+	for _, test := range tests {
+		lex := lexer.New(test.input)
+		par := New(lex)
+		program := par.ParseProgram()
+		checkParserErrors(t, par)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		exp, ok := stmt.Expression.(*ast.LogicalExpression)
+		if !ok {
+			t.Fatalf("exp is not ast.LogicalExpression. got=%T", stmt.Expression)
+		}
+
+		if exp.Left.String() != test.left || exp.Operator != test.operator || exp.Right.String() != test.right {
+			t.Errorf("wrong expression for %q. got=%q %q %q",
+				test.input, exp.Left.String(), exp.Operator, exp.Right.String())
+		}
+	}
+}
+
+func TestIfExpression(t *testing.T) {
+	input := `if (x < y) { x }`
+
+	lex := lexer.New(input)
+	par := New(lex)
+	program := par.ParseProgram()
+	checkParserErrors(t, par)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain %d statements. got=%d\n",
+			1, len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T",
+			program.Statements[0])
+	}
+
+	exp, ok := stmt.Expression.(*ast.IfExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.IfExpression. got=%T", stmt.Expression)
+	}
+
+	if exp.Condition.String() != "(x < y)" {
+		t.Errorf("exp.Condition wrong. got=%q", exp.Condition.String())
+	}
+
+	if len(exp.Consequence.Statements) != 1 {
+		t.Fatalf("consequence is not 1 statement. got=%d", len(exp.Consequence.Statements))
+	}
+
+	consequence, ok := exp.Consequence.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("Statements[0] is not ast.ExpressionStatement. got=%T",
+			exp.Consequence.Statements[0])
+	}
+
+	if consequence.Expression.String() != "x" {
+		t.Errorf("consequence wrong. got=%q", consequence.Expression.String())
+	}
+
+	if exp.Alternative != nil {
+		t.Errorf("exp.Alternative was not nil. got=%+v", exp.Alternative)
+	}
+}
+
+func TestIfElseExpressionParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"if (x < y) { x } else { y }", "if (x < y) { x } else { y }"},
+		{"if (a) { 1 } else if (b) { 2 } else { 3 }", "if a { 1 } else { if b { 2 } else { 3 } }"},
+		{"if (a) { 1 } else if (b) { 2 }", "if a { 1 } else { if b { 2 } }"},
+		{"if (a) { if (b) { 1 } else { 2 } }", "if a { if b { 1 } else { 2 } }"},
+		{"if (a) { let x = 1; if (b) { if (c) { x } } }", "if a { let x = 1;if b { if c { x } } }"},
+		{"if (a) { }", "if a {  }"},
+	}
+
+	for _, test := range tests {
+		lex := lexer.New(test.input)
+		par := New(lex)
+		program := par.ParseProgram()
+		checkParserErrors(t, par)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program.Statements does not contain 1 statement. got=%d", len(program.Statements))
+		}
+
+		actual := program.String()
+		if actual != test.expected {
+			t.Errorf("expected=%q, got=%q", test.expected, actual)
+		}
+	}
+}
+
+func TestParsingArrayLiterals(t *testing.T) {
+	tests := []struct {
+		input            string
+		expectedElements int
+		expectedString   string
+	}{
+		{"[1, 2 * 2, 3 + 3]", 3, "[1, (2 * 2), (3 + 3)]"},
+		{"[]", 0, "[]"},
+		{"[1, 2,]", 2, "[1, 2]"},
+		{"[\n  [1],\n  [],\n]", 2, "[[1], []]"},
+	}
+
+	for _, test := range tests {
+		lex := lexer.New(test.input)
+		par := New(lex)
+		program := par.ParseProgram()
+		checkParserErrors(t, par)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		array, ok := stmt.Expression.(*ast.ArrayLiteral)
+		if !ok {
+			t.Fatalf("exp not ast.ArrayLiteral. got=%T", stmt.Expression)
+		}
+
+		if len(array.Elements) != test.expectedElements {
+			t.Errorf("len(array.Elements) not %d. got=%d", test.expectedElements, len(array.Elements))
+		}
+
+		if array.String() != test.expectedString {
+			t.Errorf("array.String() wrong. expected=%q, got=%q", test.expectedString, array.String())
+		}
+	}
+}
+
+func TestParsingIndexAndSliceExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"myArray[1 + 1]", "(myArray[(1 + 1)])"},
+		{"a[-1]", "(a[(-1)])"},
+		{"a[1:3]", "(a[1:3])"},
+		{"a[:3]", "(a[:3])"},
+		{"a[1:]", "(a[1:])"},
+		{"a[:]", "(a[:])"},
+		{"a[-2:-1]", "(a[(-2):(-1)])"},
+		{"a * [1, 2, 3, 4][b * c] * d", "((a * ([1, 2, 3, 4][(b * c)])) * d)"},
+		{"add(a * b[2], b[1], 2 * [1, 2][1])", "add((a * (b[2])), (b[1]), (2 * ([1, 2][1])))"},
+		{"-a[0]", "(-(a[0]))"},
+		{"fns[0](1)", "(fns[0])(1)"},
+		{"f(1)[0]", "(f(1)[0])"},
+		{"a[0][1:2]", "((a[0])[1:2])"},
+		{"f(1, 2,)", "f(1, 2)"},
+	}
+
+	for _, test := range tests {
+		lex := lexer.New(test.input)
+		par := New(lex)
+		program := par.ParseProgram()
+		checkParserErrors(t, par)
+
+		actual := program.String()
+		if actual != test.expected {
+			t.Errorf("expected=%q, got=%q", test.expected, actual)
+		}
+	}
+}
+
+func TestMalformedArrayExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"[1, 2", "1:6: expected next token to be RBRACKET, got EOF."},
+		{"[1,, 2]", "1:4: no prefix parse function for COMMA found"},
+		{"a[]", "1:3: no prefix parse function for RBRACKET found"},
+		{"a[1:2:3]", "1:6: expected next token to be RBRACKET, got COLON."},
+	}
+
+	for _, test := range tests {
+		lex := lexer.New(test.input)
+		par := New(lex)
+		par.ParseProgram()
+
+		errors := par.Errors()
+		if len(errors) == 0 || errors[0].Error() != test.expected {
+			t.Errorf("wrong errors for %q. expected=%q, got=%v", test.input, test.expected, errors)
+		}
+	}
+}
+
+func TestParsingHashLiterals(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedPairs int
+		expected      string
+	}{
+		{`{"one": 1, "two": 2, "three": 3}`, 3, `{"one": 1, "two": 2, "three": 3}`},
+		{"{}", 0, "{}"},
+		{`{"one": 0 + 1, "two": 10 - 8, "three": 15 / 5}`, 3, `{"one": (0 + 1), "two": (10 - 8), "three": (15 / 5)}`},
+		{"{1: true, false: [1], x: {}, }", 3, "{1: true, false: [1], x: {}}"},
+		{"{z: 1, a: 2, m: 3}", 3, "{z: 1, a: 2, m: 3}"},
+	}
+
+	for _, test := range tests {
+		lex := lexer.New(test.input)
+		par := New(lex)
+		program := par.ParseProgram()
+		checkParserErrors(t, par)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		hash, ok := stmt.Expression.(*ast.HashLiteral)
+		if !ok {
+			t.Fatalf("exp is not ast.HashLiteral. got=%T", stmt.Expression)
+		}
+
+		if len(hash.Pairs) != test.expectedPairs {
+			t.Errorf("hash.Pairs has wrong length. expected=%d, got=%d", test.expectedPairs, len(hash.Pairs))
+		}
+
+		if hash.String() != test.expected {
+			t.Errorf("hash.String() wrong. expected=%q, got=%q", test.expected, hash.String())
+		}
+	}
+}
+
+func TestHashLiteralsVersusBlocks(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"if (x) { y }", "if x { y }"},
+		{"if (x) { {a: 1} }", "if x { {a: 1} }"},
+		{"let f = fn() { {} }", "let f = fn() { {} };"},
+		{`h["k"]`, `(h["k"])`},
+		{`{"a": 1}["a"]`, `({"a": 1}["a"])`},
+		{"let x = 1; {}", "let x = 1;{}"},
+		{"{x: 1}; {}", "{x: 1}{}"},
+	}
+
+	for _, test := range tests {
+		lex := lexer.New(test.input)
+		par := New(lex)
+		program := par.ParseProgram()
+		checkParserErrors(t, par)
+
+		if program.String() != test.expected {
+			t.Errorf("expected=%q, got=%q", test.expected, program.String())
+		}
+	}
+}
+
+func TestMalformedHashLiterals(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"{1 2}", "1:4: expected next token to be COLON, got INT."},
+		{"{1: 2", "1:6: expected next token to be RBRACE, got EOF."},
+		{"{1: 2 3: 4}", "1:7: expected next token to be RBRACE, got INT."},
+	}
+
+	for _, test := range tests {
+		lex := lexer.New(test.input)
+		par := New(lex)
+		par.ParseProgram()
+
+		errors := par.Errors()
+		if len(errors) == 0 || errors[0].Error() != test.expected {
+			t.Errorf("wrong errors for %q. expected=%q, got=%v", test.input, test.expected, errors)
+		}
+	}
+}
+
+func TestUnterminatedBlockReportsError(t *testing.T) {
+	lex := lexer.New("if (x) { x")
+	par := New(lex)
+	par.ParseProgram()
+
+	if len(par.Errors()) == 0 {
+		t.Fatalf("expected an error for an unterminated block")
+	}
+}
+
+func TestWhileStatement(t *testing.T) {
+	input := `while (x < 10) { if (x == 5) { break; } continue }`
+
+	lex := lexer.New(input)
+	par := New(lex)
+	program := par.ParseProgram()
+	checkParserErrors(t, par)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain %d statements. got=%d\n",
+			1, len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.WhileStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.WhileStatement. got=%T", program.Statements[0])
+	}
+
+	if stmt.Condition.String() != "(x < 10)" {
+		t.Errorf("stmt.Condition wrong. got=%q", stmt.Condition.String())
+	}
+
+	if len(stmt.Body.Statements) != 2 {
+		t.Fatalf("body is not 2 statements. got=%d", len(stmt.Body.Statements))
+	}
+
+	if _, ok := stmt.Body.Statements[1].(*ast.ContinueStatement); !ok {
+		t.Errorf("Statements[1] is not ast.ContinueStatement. got=%T", stmt.Body.Statements[1])
+	}
+
+	expected := "while (x < 10) { if (x == 5) { break; }continue; }"
+	if program.String() != expected {
+		t.Errorf("program.String() wrong. expected=%q, got=%q", expected, program.String())
+	}
+}
+
+func TestForInStatement(t *testing.T) {
+	tests := []struct {
+		input            string
+		expectedKey      string
+		expectedValue    string
+		expectedIterable string
+		expected         string
+	}{
+		{"for (x in xs) { x }", "", "x", "xs", "for x in xs { x }"},
+		{"for (k, v in h) { break }", "k", "v", "h", "for k, v in h { break; }"},
+		{"for (i in 0..=n + 1) { continue; }", "", "i", "(0..=(n + 1))", "for i in (0..=(n + 1)) { continue; }"},
+		{"for (c in f(\"abc\")) {}", "", "c", "f(\"abc\")", "for c in f(\"abc\") {  }"},
+	}
+
+	for _, test := range tests {
+		lex := lexer.New(test.input)
+		par := New(lex)
+		program := par.ParseProgram()
+		checkParserErrors(t, par)
+
+		stmt, ok := program.Statements[0].(*ast.ForInStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not ast.ForInStatement. got=%T", program.Statements[0])
+		}
+
+		if test.expectedKey == "" && stmt.Key != nil {
+			t.Errorf("stmt.Key should be nil. got=%q", stmt.Key.String())
+		}
+		if test.expectedKey != "" && (stmt.Key == nil || stmt.Key.Value != test.expectedKey) {
+			t.Errorf("stmt.Key wrong. expected=%q, got=%v", test.expectedKey, stmt.Key)
+		}
+
+		if stmt.Value.Value != test.expectedValue {
+			t.Errorf("stmt.Value wrong. expected=%q, got=%q", test.expectedValue, stmt.Value.Value)
+		}
+
+		if stmt.Iterable.String() != test.expectedIterable {
+			t.Errorf("stmt.Iterable wrong. expected=%q, got=%q", test.expectedIterable, stmt.Iterable.String())
+		}
+
+		if program.String() != test.expected {
+			t.Errorf("program.String() wrong. expected=%q, got=%q", test.expected, program.String())
+		}
+	}
+}
+
+func TestMalformedForInStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"for x in xs { }", "1:5: expected next token to be LPAREN, got IDENTIFIER."},
+		{"for (1 in xs) { }", "1:6: expected next token to be IDENTIFIER, got INT."},
+		{"for (k, in h) { }", "1:9: expected next token to be IDENTIFIER, got IN."},
+		{"for (k, v, w in h) { }", "1:10: expected next token to be IN, got COMMA."},
+		{"for (x of xs) { }", "1:8: expected next token to be IN, got IDENTIFIER."},
+		{"for (x in xs) x", "1:15: expected next token to be LBRACE, got IDENTIFIER."},
+	}
+
+	for _, test := range tests {
+		lex := lexer.New(test.input)
+		par := New(lex)
+		par.ParseProgram()
+
+		errors := par.Errors()
+		if len(errors) == 0 || errors[0].Error() != test.expected {
+			t.Errorf("wrong errors for %q. expected=%q, got=%v", test.input, test.expected, errors)
+		}
+	}
+}
+
+func TestLoopControlOutsideLoop(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"break;", []string{"1:1: break is not inside a loop."}},
+		{"let x = 1;\n  continue", []string{"2:3: continue is not inside a loop."}},
+		{"if (x) { break } else { continue }", []string{
+			"1:10: break is not inside a loop.",
+			"1:25: continue is not inside a loop.",
+		}},
+		{"while (true) { let f = fn() { break; }; }", []string{"1:31: break is not inside a loop."}},
+		{"while (true) { } break", []string{"1:18: break is not inside a loop."}},
+		{"for (x in xs) { fn() { continue } }", []string{"1:24: continue is not inside a loop."}},
+	}
+
+	for _, test := range tests {
+		lex := lexer.New(test.input)
+		par := New(lex)
+		par.ParseProgram()
+
+		errors := par.Errors()
+		if len(errors) != len(test.expected) {
+			t.Errorf("wrong number of errors for %q. expected=%d, got=%v", test.input, len(test.expected), errors)
+			continue
+		}
+
+		for i, err := range errors {
+			if err.Error() != test.expected[i] {
+				t.Errorf("errors[%d] wrong for %q. expected=%q, got=%q", i, test.input, test.expected[i], err.Error())
+			}
+		}
+	}
+}
+
+func TestLoopControlInsideLoop(t *testing.T) {
+	inputs := []string{
+		"while (true) { break }",
+		"while (a) { while (b) { continue; } break; }",
+		"while (a) { if (b) { if (c) { break } } }",
+		"while (a) { fn() { while (b) { break } }; continue }",
+		"for (x in xs) { if (x) { break } continue }",
+		"for (k, v in h) { for (x in v) { break } }",
+		"while (a) { }; for (x in xs) { };",
+	}
+
+	for _, input := range inputs {
+		lex := lexer.New(input)
+		par := New(lex)
+		par.ParseProgram()
+		checkParserErrors(t, par)
+	}
+}
+
+func TestFunctionLiteralParsing(t *testing.T) {
+	input := `fn(x, y) { x + y; }`
+
+	lex := lexer.New(input)
+	par := New(lex)
+	program := par.ParseProgram()
+	checkParserErrors(t, par)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain %d statements. got=%d\n",
+			1, len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T",
+			program.Statements[0])
+	}
+
+	function, ok := stmt.Expression.(*ast.FunctionLiteral)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.FunctionLiteral. got=%T", stmt.Expression)
+	}
+
+	if len(function.Parameters) != 2 {
+		t.Fatalf("function literal parameters wrong. want 2, got=%d\n", len(function.Parameters))
+	}
+
+	if function.Parameters[0].Value != "x" || function.Parameters[1].Value != "y" {
+		t.Errorf("function literal parameters wrong. got=%v", function.Parameters)
+	}
+
+	if len(function.Body.Statements) != 1 {
+		t.Fatalf("function.Body.Statements has not 1 statement. got=%d\n", len(function.Body.Statements))
+	}
+
+	if function.Body.String() != "{ (x + y) }" {
+		t.Errorf("function.Body wrong. got=%q", function.Body.String())
+	}
+}
+
+func TestFunctionParameterParsing(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedParams []string
+	}{
+		{input: "fn() {};", expectedParams: []string{}},
+		{input: "fn(x) {};", expectedParams: []string{"x"}},
+		{input: "fn(x, y, z) {};", expectedParams: []string{"x", "y", "z"}},
+	}
+
+	for _, test := range tests {
+		lex := lexer.New(test.input)
+		par := New(lex)
+		program := par.ParseProgram()
+		checkParserErrors(t, par)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		function := stmt.Expression.(*ast.FunctionLiteral)
+
+		if len(function.Parameters) != len(test.expectedParams) {
+			t.Errorf("length parameters wrong. want %d, got=%d\n",
+				len(test.expectedParams), len(function.Parameters))
+		}
+
+		for i, ident := range test.expectedParams {
+			if function.Parameters[i].Value != ident {
+				t.Errorf("parameter %d wrong. want %s, got=%s", i, ident, function.Parameters[i].Value)
+			}
+		}
+	}
+}
+
+func TestCallExpressionParsing(t *testing.T) {
+	input := "add(1, 2 * 3, 4 + 5);"
+
+	lex := lexer.New(input)
+	par := New(lex)
+	program := par.ParseProgram()
+	checkParserErrors(t, par)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain %d statements. got=%d\n",
+			1, len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("stmt is not ast.ExpressionStatement. got=%T",
+			program.Statements[0])
+	}
+
+	exp, ok := stmt.Expression.(*ast.CallExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.CallExpression. got=%T", stmt.Expression)
+	}
+
+	if exp.Function.String() != "add" {
+		t.Errorf("exp.Function wrong. got=%q", exp.Function.String())
+	}
+
+	if len(exp.Arguments) != 3 {
+		t.Fatalf("wrong length of arguments. got=%d", len(exp.Arguments))
+	}
+
+	testIntegerLiteral(t, exp.Arguments[0], 1)
+	if exp.Arguments[1].String() != "(2 * 3)" {
+		t.Errorf("exp.Arguments[1] wrong. got=%q", exp.Arguments[1].String())
+	}
+	if exp.Arguments[2].String() != "(4 + 5)" {
+		t.Errorf("exp.Arguments[2] wrong. got=%q", exp.Arguments[2].String())
+	}
+}
+
+func TestCallExpressionPrecedence(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"a + add(b * c) + d", "((a + add((b * c))) + d)"},
+		{"add(a, b, 1, 2 * 3, 4 + 5, add(6, 7 * 8))", "add(a, b, 1, (2 * 3), (4 + 5), add(6, (7 * 8)))"},
+		{"add(a + b + c * d / f + g)", "add((((a + b) + ((c * d) / f)) + g))"},
+		{"-f(x)", "(-f(x))"},
+		{"fn(x) { x }(5)", "fn(x) { x }(5)"},
+		{"f(1)(2)", "f(1)(2)"},
+		{"f()(1, 2)(3)", "f()(1, 2)(3)"},
+	}
+
+	for _, test := range tests {
+		lex := lexer.New(test.input)
+		par := New(lex)
+		program := par.ParseProgram()
+		checkParserErrors(t, par)
+
+		actual := program.String()
+		if actual != test.expected {
+			t.Errorf("expected=%q, got=%q", test.expected, actual)
+		}
+	}
+}
+
+func TestBooleanExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"true;", true},
+		{"false;", false},
+	}
+
+	for _, test := range tests {
+		lex := lexer.New(test.input)
+		par := New(lex)
+		program := par.ParseProgram()
+		checkParserErrors(t, par)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program.Statements does not contain 1 statement. got=%d", len(program.Statements))
+		}
+
+		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T",
+				program.Statements[0])
+		}
+
+		boolean, ok := stmt.Expression.(*ast.Boolean)
+		if !ok {
+			t.Fatalf("exp not *ast.Boolean. got=%T", stmt.Expression)
+		}
+
+		if boolean.Value != test.expected {
+			t.Errorf("boolean.Value not %t. got=%t", test.expected, boolean.Value)
+		}
+	}
+}
+
+func TestUnclosedGroupReportsError(t *testing.T) {
+	lex := lexer.New("(1 + 2")
+	par := New(lex)
+	par.ParseProgram()
+
+	if len(par.Errors()) == 0 {
+		t.Fatalf("expected an error for an unclosed group")
+	}
+}
+
+func TestErrorMessagesIncludePositions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let x 5;", "1:7: expected next token to be ASSIGN, got INT."},
+		{"let x = 1;\nlet = 2;", "2:5: expected next token to be IDENTIFIER, got ASSIGN."},
+		{"1 +\n  ;", "2:3: no prefix parse function for SEMICOLON found"},
+		{"if (x) {\n  x", "2:4: expected RBRACE to close block, got EOF."},
+	}
+
+	for _, test := range tests {
+		lex := lexer.New(test.input)
+		par := New(lex)
+		par.ParseProgram()
+
+		errors := par.Errors()
+		if len(errors) == 0 {
+			t.Errorf("expected errors for %q, got none", test.input)
+			continue
+		}
+
+		if errors[0].Error() != test.expected {
+			t.Errorf("wrong first error for %q. expected=%q, got=%q", test.input, test.expected, errors[0].Error())
+		}
+	}
+}
+
+func TestNodePositions(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedStart string
+		expectedEnd   string
+	}{
+		{"let x = 5;", "1:1", "1:10"},
+		{"  a + bc", "1:3", "1:9"},
+		{"add(1,\n 2)", "1:1", "2:4"},
+		{"if (x) { y } else { z }", "1:1", "1:24"},
+		{"fn(a) {\n  a\n}", "1:1", "3:2"},
+		{"-5", "1:1", "1:3"},
+	}
+
+	for _, test := range tests {
+		lex := lexer.New(test.input)
+		par := New(lex)
+		program := par.ParseProgram()
+		checkParserErrors(t, par)
+
+		stmt := program.Statements[0]
+		if stmt.Pos().String() != test.expectedStart {
+			t.Errorf("wrong start for %q. expected=%s, got=%s", test.input, test.expectedStart, stmt.Pos())
+		}
+		if stmt.End().String() != test.expectedEnd {
+			t.Errorf("wrong end for %q. expected=%s, got=%s", test.input, test.expectedEnd, stmt.End())
+		}
+	}
+}
+
+func TestParseErrorFields(t *testing.T) {
+	lex := lexer.New("let x 5;")
+	par := New(lex)
+	par.ParseProgram()
+
+	errors := par.Errors()
+	if len(errors) != 1 {
+		t.Fatalf("expected 1 error, got %d", len(errors))
+	}
+
+	err := errors[0]
+	if err.Expected != token.ASSIGN {
+		t.Errorf("err.Expected wrong. expected=%s, got=%s", token.ASSIGN, err.Expected)
+	}
+	if err.Found.Type != token.INT || err.Found.Literal != "5" {
+		t.Errorf("err.Found wrong. got=%+v", err.Found)
+	}
+	if err.Pos.String() != "1:7" {
+		t.Errorf("err.Pos wrong. got=%s", err.Pos)
+	}
+	if err.Message != "expected next token to be ASSIGN, got INT." {
+		t.Errorf("err.Message wrong. got=%q", err.Message)
+	}
+}
+
+func TestErrorRecovery(t *testing.T) {
+	tests := []struct {
+		input              string
+		expectedErrors     []string
+		expectedStatements string
+	}{
+		{
+			"let x 5; let y = 2; let = 3; y;",
+			[]string{
+				"1:7: expected next token to be ASSIGN, got INT.",
+				"1:25: expected next token to be IDENTIFIER, got ASSIGN.",
+			},
+			"let y = 2;y",
+		},
+		{
+			"let x 5\nlet y = 2",
+			[]string{"1:7: expected next token to be ASSIGN, got INT."},
+			"let y = 2;",
+		},
+		{
+			"let f = fn() { let = 1; 2 }; f; let g = fn() { 1 + ; }; g",
+			[]string{
+				"1:20: expected next token to be IDENTIFIER, got ASSIGN.",
+				"1:52: no prefix parse function for SEMICOLON found",
+			},
+			"fg",
+		},
+		{
+			"if (x) { 1 +; 2 }; 3",
+			[]string{"1:13: no prefix parse function for SEMICOLON found"},
+			"3",
+		},
+		{
+			"if (x { 1 }; let y = 2 +; 3",
+			[]string{
+				"1:7: expected next token to be RPAREN, got LBRACE.",
+				"1:25: no prefix parse function for SEMICOLON found",
+			},
+			"3",
+		},
+		{
+			"let x = 1; { x }",
+			[]string{"1:16: expected next token to be COLON, got RBRACE."},
+			"let x = 1;",
+		},
+		{
+			"(1 + 2; 4 * 5",
+			[]string{"1:7: expected next token to be RPAREN, got SEMICOLON."},
+			"(4 * 5)",
+		},
+	}
+
+	for _, test := range tests {
+		lex := lexer.New(test.input)
+		par := New(lex)
+		program := par.ParseProgram()
+
+		errors := par.Errors()
+		if len(errors) != len(test.expectedErrors) {
+			t.Errorf("wrong number of errors for %q. expected=%d, got=%d (%v)",
+				test.input, len(test.expectedErrors), len(errors), errors)
+			continue
+		}
+
+		for i, expected := range test.expectedErrors {
+			if errors[i].Error() != expected {
+				t.Errorf("errors[%d] wrong for %q. expected=%q, got=%q", i, test.input, expected, errors[i].Error())
+			}
+		}
+
+		for i, stmt := range program.Statements {
+			if stmt == nil {
+				t.Fatalf("program.Statements[%d] is nil for %q", i, test.input)
+			}
+		}
+
+		if program.String() != test.expectedStatements {
+			t.Errorf("wrong surviving statements for %q. expected=%q, got=%q",
+				test.input, test.expectedStatements, program.String())
+		}
+	}
+}
+
+func TestStringLiteralExpression(t *testing.T) {
+	input := `"hello\tworld";`
+
+	lex := lexer.New(input)
+	par := New(lex)
+	program := par.ParseProgram()
+	checkParserErrors(t, par)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	literal, ok := stmt.Expression.(*ast.StringLiteral)
+	if !ok {
+		t.Fatalf("exp not *ast.StringLiteral. got=%T", stmt.Expression)
+	}
+
+	if literal.Value != "hello\tworld" {
+		t.Errorf("literal.Value not %q. got=%q", "hello\tworld", literal.Value)
+	}
+
+	if literal.String() != input[:len(input)-1] {
+		t.Errorf("literal.String() not %q. got=%q", input[:len(input)-1], literal.String())
+	}
+}
+
+func TestInterpolatedStringParsing(t *testing.T) {
+	tests := []struct {
+		input            string
+		expectedSegments int
+		expectedString   string
+	}{
+		{`"total: ${a + b}"`, 3, `"total: ${(a + b)}"`},
+		{`"${x}"`, 3, `"${x}"`},
+		{`"a${x}b${f(1, 2)}c"`, 5, `"a${x}b${f(1, 2)}c"`},
+		{`"outer ${"inner ${x}"}"`, 3, `"outer ${"inner ${x}"}"`},
+		{`"tab\t${x}\${y}"`, 3, `"tab\t${x}\${y}"`},
+	}
+
+	for _, test := range tests {
+		lex := lexer.New(test.input)
+		par := New(lex)
+		program := par.ParseProgram()
+		checkParserErrors(t, par)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		str, ok := stmt.Expression.(*ast.InterpolatedString)
+		if !ok {
+			t.Fatalf("exp not *ast.InterpolatedString. got=%T", stmt.Expression)
+		}
+
+		if len(str.Segments) != test.expectedSegments {
+			t.Errorf("wrong number of segments for %s. expected=%d, got=%d",
+				test.input, test.expectedSegments, len(str.Segments))
+		}
+
+		if str.String() != test.expectedString {
+			t.Errorf("str.String() wrong. expected=%s, got=%s", test.expectedString, str.String())
+		}
+	}
+}
+
+func TestInterpolatedStringErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`"a ${} b"`, "1:6: no prefix parse function for STRING_END found"},
+		{`"a ${x y} b"`, "1:8: expected next token to be STRING_END, got IDENTIFIER."},
+	}
+
+	for _, test := range tests {
+		lex := lexer.New(test.input)
+		par := New(lex)
+		par.ParseProgram()
+
+		errors := par.Errors()
+		if len(errors) == 0 || errors[0].Error() != test.expected {
+			t.Errorf("wrong errors for %s. expected=%q, got=%v", test.input, test.expected, errors)
+		}
+	}
+}
+
+func TestParserSkipsComments(t *testing.T) {
+	input := "let x = 1; // one\n/* two */ x + /* three */ 2"
+
+	for _, lex := range []*lexer.Lexer{lexer.New(input), lexer.New(input, lexer.WithComments())} {
+		par := New(lex)
+		program := par.ParseProgram()
+		checkParserErrors(t, par)
+
+		if program.String() != "let x = 1;(x + 2)" {
+			t.Errorf("program.String() wrong. got=%q", program.String())
+		}
+	}
+}
+
+func TestLexerErrorsAreReported(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedErrors []string
+	}{
+		{`let s = "abc`, []string{"1:9: unterminated string literal"}},
+		{`let s = "a\qb"; s`, []string{`1:11: unknown escape sequence \q`}},
+		{`let x 5 "\q"; 1`, []string{
+			"1:7: expected next token to be ASSIGN, got INT.",
+			`1:10: unknown escape sequence \q`,
+		}},
+		{"let x = 1; /* never closed", []string{"1:12: unterminated block comment"}},
+		{"let mask = 0b102;", []string{"1:16: invalid digit '2' in binary literal"}},
+		{"0x8000_0000_0000_0000", []string{`1:1: could not parse "0x8000_0000_0000_0000" as integer`}},
+		{"let half = .5;", []string{"1:12: float literal must have a digit before the decimal point, e.g. 0.5"}},
+		{`"\q\u{}"`, []string{
+			`1:2: unknown escape sequence \q`,
+			`1:4: \u{...} escape must have between 1 and 6 hex digits`,
+		}},
+	}
+
+	for _, test := range tests {
+		lex := lexer.New(test.input)
+		par := New(lex)
+		par.ParseProgram()
+
+		errors := par.Errors()
+		if len(errors) != len(test.expectedErrors) {
+			t.Errorf("wrong number of errors for %q. expected=%d, got=%d (%v)",
+				test.input, len(test.expectedErrors), len(errors), errors)
+			continue
+		}
+
+		for i, expected := range test.expectedErrors {
+			if errors[i].Error() != expected {
+				t.Errorf("errors[%d] wrong for %q. expected=%q, got=%q", i, test.input, expected, errors[i].Error())
+			}
+		}
+	}
+}
+
 func testLetStatement(t testing.TB, parsedStmt ast.Statement, expectedName string) {
 	t.Helper()
 
@@ -217,8 +1641,8 @@ func checkParserErrors(t testing.TB, p *Parser) {
 	errors := p.Errors()
 	if len(errors) > 0 {
 		t.Errorf("parser had %d errors", len(errors))
-		for _, msg := range errors {
-			t.Errorf("parser error: %q", msg)
+		for _, err := range errors {
+			t.Errorf("parser error: %q", err.Error())
 		}
 		t.FailNow()
 	}
@@ -0,0 +1,156 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/MichaelBo1/go_interpreter/ast"
+)
+
+// scope records the names declared in one scope and whether each is a constant. Scopes
+// mirror the environments the evaluator creates: one for the program, each block, each
+// loop iteration and each function call, where the parameters share the body's scope.
+type scope map[string]bool
+
+// resolver walks a parsed program checking how names are bound, so that a script which
+// assigns to a constant, or declares a name again in the scope of a constant with that
+// name, is rejected before any of it runs. Names it never sees declared, such as those
+// bound by earlier REPL lines, aren't treated as constants; the evaluator checks those.
+type resolver struct {
+	scopes []scope
+	errors []ParseError
+}
+
+func resolve(program *ast.Program) []ParseError {
+	r := &resolver{scopes: []scope{{}}}
+	r.resolveStatements(program.Statements)
+	return r.errors
+}
+
+func (r *resolver) resolveStatements(statements []ast.Statement) {
+	for _, statement := range statements {
+		r.resolveNode(statement)
+	}
+}
+
+// resolveInScope resolves a block in a new scope, with names declared in it up front.
+func (r *resolver) resolveInScope(block *ast.BlockStatement, names ...*ast.Identifier) {
+	r.scopes = append(r.scopes, scope{})
+	for _, name := range names {
+		r.declare(name, false)
+	}
+	r.resolveStatements(block.Statements)
+	r.scopes = r.scopes[:len(r.scopes)-1]
+}
+
+func (r *resolver) resolveNode(node ast.Node) {
+	switch node := node.(type) {
+
+	// Statements
+	case *ast.LetStatement:
+		// The value is resolved first, as it can't refer to the name being declared.
+		r.resolveNode(node.Value)
+		r.declare(node.Name, false)
+	case *ast.ConstStatement:
+		r.resolveNode(node.Value)
+		r.declare(node.Name, true)
+	case *ast.ReturnStatement:
+		r.resolveNode(node.Value)
+	case *ast.ExpressionStatement:
+		r.resolveNode(node.Expression)
+	case *ast.BlockStatement:
+		r.resolveInScope(node)
+	case *ast.WhileStatement:
+		r.resolveNode(node.Condition)
+		r.resolveInScope(node.Body)
+	case *ast.ForInStatement:
+		r.resolveNode(node.Iterable)
+		if node.Key != nil {
+			r.resolveInScope(node.Body, node.Key, node.Value)
+		} else {
+			r.resolveInScope(node.Body, node.Value)
+		}
+
+	// Expressions
+	case *ast.InterpolatedString:
+		for _, segment := range node.Segments {
+			r.resolveNode(segment)
+		}
+	case *ast.PrefixExpression:
+		r.resolveNode(node.Right)
+	case *ast.InfixExpression:
+		r.resolveNode(node.Left)
+		r.resolveNode(node.Right)
+	case *ast.LogicalExpression:
+		r.resolveNode(node.Left)
+		r.resolveNode(node.Right)
+	case *ast.RangeExpression:
+		r.resolveNode(node.Low)
+		r.resolveNode(node.High)
+	case *ast.AssignExpression:
+		if target, ok := node.Target.(*ast.Identifier); ok && r.isConst(target.Value) {
+			r.errors = append(r.errors, ParseError{
+				Pos:     target.Pos(),
+				Found:   target.Token,
+				Message: fmt.Sprintf("cannot assign to constant %s.", target.Value),
+			})
+		}
+		r.resolveNode(node.Target)
+		r.resolveNode(node.Value)
+	case *ast.IfExpression:
+		r.resolveNode(node.Condition)
+		r.resolveNode(node.Consequence)
+		if node.Alternative != nil {
+			r.resolveNode(node.Alternative)
+		}
+	case *ast.FunctionLiteral:
+		r.resolveInScope(node.Body, node.Parameters...)
+	case *ast.CallExpression:
+		r.resolveNode(node.Function)
+		for _, argument := range node.Arguments {
+			r.resolveNode(argument)
+		}
+	case *ast.ArrayLiteral:
+		for _, element := range node.Elements {
+			r.resolveNode(element)
+		}
+	case *ast.HashLiteral:
+		for _, pair := range node.Pairs {
+			r.resolveNode(pair.Key)
+			r.resolveNode(pair.Value)
+		}
+	case *ast.IndexExpression:
+		r.resolveNode(node.Left)
+		r.resolveNode(node.Index)
+	case *ast.SliceExpression:
+		r.resolveNode(node.Left)
+		r.resolveNode(node.Low) // Either bound may be nil, which resolves to nothing.
+		r.resolveNode(node.High)
+	}
+}
+
+// declare adds name to the innermost scope. A name may be declared again with let, but not
+// if either declaration is a constant.
+func (r *resolver) declare(name *ast.Identifier, constant bool) {
+	current := r.scopes[len(r.scopes)-1]
+
+	if wasConstant, ok := current[name.Value]; ok && (wasConstant || constant) {
+		message := fmt.Sprintf("%s is already declared in this scope.", name.Value)
+		if wasConstant {
+			message = fmt.Sprintf("cannot redeclare constant %s.", name.Value)
+		}
+		r.errors = append(r.errors, ParseError{Pos: name.Pos(), Found: name.Token, Message: message})
+		return
+	}
+
+	current[name.Value] = constant
+}
+
+// isConst reports whether the innermost declaration of name is a constant.
+func (r *resolver) isConst(name string) bool {
+	for i := len(r.scopes) - 1; i >= 0; i-- {
+		if constant, ok := r.scopes[i][name]; ok {
+			return constant
+		}
+	}
+	return false
+}
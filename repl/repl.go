@@ -5,17 +5,23 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/MichaelBo1/go_interpreter/diagnostics"
+	"github.com/MichaelBo1/go_interpreter/evaluator"
 	"github.com/MichaelBo1/go_interpreter/lexer"
-	"github.com/MichaelBo1/go_interpreter/token"
+	"github.com/MichaelBo1/go_interpreter/object"
+	"github.com/MichaelBo1/go_interpreter/parser"
 )
 
 const PROMPT = "-> "
 
+// Run reads one line at a time from in, evaluates it and writes the result to out.
+// Bindings persist between lines, so later lines can use values defined earlier.
 func Run(in io.Reader, out io.Writer) {
 	scanner := bufio.NewScanner(in)
+	env := object.NewEnvironment()
 
 	for {
-		fmt.Print(PROMPT)
+		fmt.Fprint(out, PROMPT)
 
 		if !scanner.Scan() {
 			return
@@ -23,9 +29,22 @@ func Run(in io.Reader, out io.Writer) {
 		line := scanner.Text()
 
 		lex := lexer.New(line)
+		par := parser.New(lex)
 
-		for tok := lex.NextToken(); tok.Type != token.EOF; tok = lex.NextToken() {
-			fmt.Printf("%+v\n", tok)
+		program := par.ParseProgram()
+		if len(par.Errors()) != 0 {
+			printParserErrors(out, line, par.Errors())
+			continue
+		}
+
+		evaluated := evaluator.Eval(program, env)
+		if evaluated != nil {
+			fmt.Fprintln(out, evaluated.Inspect())
 		}
 	}
 }
+
+func printParserErrors(out io.Writer, line string, errors []parser.ParseError) {
+	fmt.Fprintln(out, "Whoops! That line couldn't be parsed:")
+	diagnostics.Fprint(out, line, diagnostics.FromParseErrors(errors))
+}
@@ -0,0 +1,61 @@
+package repl
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRunEvaluatesLines(t *testing.T) {
+	input := strings.Join([]string{
+		"let a = 5;",
+		"let add = fn(x, y) { x + y };",
+		"add(a, 10)",
+		"a > 3",
+	}, "\n")
+
+	var out bytes.Buffer
+	Run(strings.NewReader(input), &out)
+
+	expected := PROMPT + PROMPT + PROMPT + "15\n" + PROMPT + "true\n" + PROMPT
+	if out.String() != expected {
+		t.Errorf("unexpected output.\nexpected=%q\ngot=%q", expected, out.String())
+	}
+}
+
+func TestRunReportsParserErrors(t *testing.T) {
+	var out bytes.Buffer
+	Run(strings.NewReader("let = 5;\n1 + 1"), &out)
+
+	output := out.String()
+	if !strings.Contains(output, "Whoops! That line couldn't be parsed:") {
+		t.Errorf("expected parser error header in output. got=%q", output)
+	}
+	if !strings.Contains(output, "expected next token to be IDENTIFIER") {
+		t.Errorf("expected parser error message in output. got=%q", output)
+	}
+	if !strings.HasSuffix(output, PROMPT+"2\n"+PROMPT) {
+		t.Errorf("expected REPL to keep going after an error. got=%q", output)
+	}
+}
+
+func TestRunReportsEvaluationErrors(t *testing.T) {
+	var out bytes.Buffer
+	Run(strings.NewReader("missing"), &out)
+
+	if !strings.Contains(out.String(), "ERROR: identifier not found: missing") {
+		t.Errorf("expected evaluation error in output. got=%q", out.String())
+	}
+}
+
+func TestRunKeepsConstantsAcrossLines(t *testing.T) {
+	var out bytes.Buffer
+	Run(strings.NewReader("const limit = 10;\nlimit = 20\nlimit"), &out)
+
+	if !strings.Contains(out.String(), "ERROR: cannot assign to constant limit") {
+		t.Errorf("expected assignment to constant to fail. got=%q", out.String())
+	}
+	if !strings.HasSuffix(out.String(), PROMPT+"10\n"+PROMPT) {
+		t.Errorf("expected constant to keep its value. got=%q", out.String())
+	}
+}
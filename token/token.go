@@ -1,20 +1,54 @@
 package token
 
+import "fmt"
+
 type TokenType int
 
 type Token struct {
 	Type    TokenType
 	Literal string
+	Pos     Position // Position of the first character of the token.
+	End     Position // Position immediately after the last character of the token.
+}
+
+// Position locates a point in the source. Offset is a 0-based byte offset, while Line and
+// Column are 1-based. The zero Position is used for tokens that didn't come from a lexer.
+type Position struct {
+	Filename string
+	Offset   int
+	Line     int
+	Column   int
+}
+
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+// String formats the position as `file:line:column`, dropping the filename if there isn't one.
+func (p Position) String() string {
+	if !p.IsValid() {
+		return "-"
+	}
+	if p.Filename == "" {
+		return fmt.Sprintf("%d:%d", p.Line, p.Column)
+	}
+	return fmt.Sprintf("%s:%d:%d", p.Filename, p.Line, p.Column)
 }
 
 var keywords = map[string]TokenType{
-	"fn":     FUNCTION,
-	"let":    LET,
-	"if":     IF,
-	"else":   ELSE,
-	"return": RETURN,
-	"true":   TRUE,
-	"false":  FALSE,
+	"fn":       FUNCTION,
+	"let":      LET,
+	"const":    CONST,
+	"if":       IF,
+	"else":     ELSE,
+	"return":   RETURN,
+	"while":    WHILE,
+	"for":      FOR,
+	"in":       IN,
+	"break":    BREAK,
+	"continue": CONTINUE,
+	"true":     TRUE,
+	"false":    FALSE,
 }
 
 func FindIdentifier(identifier string) TokenType {
@@ -26,40 +60,80 @@ func FindIdentifier(identifier string) TokenType {
 
 const (
 	UNKNOWN TokenType = iota
+	ILLEGAL           // A malformed token; the lexer reports why alongside it.
 	EOF
+	COMMENT // Only produced when the lexer is asked to keep comments.
 
 	IDENTIFIER
 	INT
+	FLOAT
+	STRING
+
+	// An interpolated string such as "a${x}b${y}c" is lexed as STRING_START ("a"), the
+	// tokens of x, STRING_MID ("b"), the tokens of y, then STRING_END ("c").
+	STRING_START
+	STRING_MID
+	STRING_END
 
 	ASSIGN
 	PLUS
 	MINUS
 
+	// Compound assignment operators.
+	PLUS_ASSIGN
+	MINUS_ASSIGN
+	ASTERISK_ASSIGN
+	SLASH_ASSIGN
+	PERCENT_ASSIGN
+
 	EQ
 	NOT_EQ
 
 	SLASH
 	BANG
 	ASTERISK
+	PERCENT
+	POWER // **
 
 	LESS_THAN
 	LESS_THAN_OR_EQ
 	GREATER_THAN
 	GREATER_THAN_OR_EQ
 
+	AND
+	OR
+
+	AMPERSAND
+	PIPE
+	CARET
+	TILDE
+	SHIFT_LEFT
+	SHIFT_RIGHT
+
 	COMMA
 	SEMICOLON
+	COLON
+	DOTDOT    // ..
+	DOTDOT_EQ // ..=
 
 	LPAREN
 	RPAREN
 	LBRACE
 	RBRACE
+	LBRACKET
+	RBRACKET
 
 	FUNCTION
 	LET
+	CONST
 	IF
 	ELSE
 	RETURN
+	WHILE
+	FOR
+	IN
+	BREAK
+	CONTINUE
 	TRUE
 	FALSE
 )
@@ -76,18 +150,42 @@ func (t TokenType) String() string {
 	switch t {
 	case UNKNOWN:
 		return "UNKNOWN"
+	case ILLEGAL:
+		return "ILLEGAL"
 	case EOF:
 		return "EOF"
+	case COMMENT:
+		return "COMMENT"
 	case IDENTIFIER:
 		return "IDENTIFIER"
 	case INT:
 		return "INT"
+	case FLOAT:
+		return "FLOAT"
+	case STRING:
+		return "STRING"
+	case STRING_START:
+		return "STRING_START"
+	case STRING_MID:
+		return "STRING_MID"
+	case STRING_END:
+		return "STRING_END"
 	case ASSIGN:
 		return "ASSIGN"
 	case PLUS:
 		return "PLUS"
 	case MINUS:
 		return "MINUS"
+	case PLUS_ASSIGN:
+		return "PLUS_ASSIGN"
+	case MINUS_ASSIGN:
+		return "MINUS_ASSIGN"
+	case ASTERISK_ASSIGN:
+		return "ASTERISK_ASSIGN"
+	case SLASH_ASSIGN:
+		return "SLASH_ASSIGN"
+	case PERCENT_ASSIGN:
+		return "PERCENT_ASSIGN"
 	case EQ:
 		return "EQ"
 	case NOT_EQ:
@@ -98,6 +196,10 @@ func (t TokenType) String() string {
 		return "BANG"
 	case ASTERISK:
 		return "ASTERISK"
+	case PERCENT:
+		return "PERCENT"
+	case POWER:
+		return "POWER"
 	case LESS_THAN:
 		return "LESS_THAN"
 	case LESS_THAN_OR_EQ:
@@ -106,10 +208,32 @@ func (t TokenType) String() string {
 		return "GREATER_THAN"
 	case GREATER_THAN_OR_EQ:
 		return "GREATER_THAN_OR_EQ"
+	case AND:
+		return "AND"
+	case OR:
+		return "OR"
+	case AMPERSAND:
+		return "AMPERSAND"
+	case PIPE:
+		return "PIPE"
+	case CARET:
+		return "CARET"
+	case TILDE:
+		return "TILDE"
+	case SHIFT_LEFT:
+		return "SHIFT_LEFT"
+	case SHIFT_RIGHT:
+		return "SHIFT_RIGHT"
 	case COMMA:
 		return "COMMA"
 	case SEMICOLON:
 		return "SEMICOLON"
+	case COLON:
+		return "COLON"
+	case DOTDOT:
+		return "DOTDOT"
+	case DOTDOT_EQ:
+		return "DOTDOT_EQ"
 	case LPAREN:
 		return "LPAREN"
 	case RPAREN:
@@ -118,16 +242,32 @@ func (t TokenType) String() string {
 		return "LBRACE"
 	case RBRACE:
 		return "RBRACE"
+	case LBRACKET:
+		return "LBRACKET"
+	case RBRACKET:
+		return "RBRACKET"
 	case FUNCTION:
 		return "FUNCTION"
 	case LET:
 		return "LET"
+	case CONST:
+		return "CONST"
 	case IF:
 		return "IF"
 	case ELSE:
 		return "ELSE"
 	case RETURN:
 		return "RETURN"
+	case WHILE:
+		return "WHILE"
+	case FOR:
+		return "FOR"
+	case IN:
+		return "IN"
+	case BREAK:
+		return "BREAK"
+	case CONTINUE:
+		return "CONTINUE"
 	case TRUE:
 		return "TRUE"
 	case FALSE: